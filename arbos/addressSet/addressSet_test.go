@@ -176,6 +176,33 @@ func TestAddressSetAllMembers(t *testing.T) {
 	}
 }
 
+func TestAddressSetOrder(t *testing.T) {
+	sto := storage.NewMemoryBacked(burn.NewSystemBurner(nil, false))
+	Require(t, Initialize(sto))
+	aset := OpenAddressSet(sto)
+	version := params.ArbitrumDevTestParams().InitialArbOSVersion
+
+	addrs := make([]common.Address, 5)
+	for i := range addrs {
+		addrs[i] = testhelpers.RandomAddress()
+	}
+	for _, addr := range addrs[:4] {
+		Require(t, aset.Add(addr))
+	}
+	// removing from the middle moves the last member into the vacated slot
+	Require(t, aset.Remove(addrs[1], version))
+	Require(t, aset.Add(addrs[4]))
+
+	expected := []common.Address{addrs[0], addrs[3], addrs[2], addrs[4]}
+	for i := 0; i < 3; i++ {
+		members, err := aset.AllMembers(math.MaxUint64)
+		Require(t, err)
+		if !cmp.Equal(members, expected) {
+			Fail(t, "unexpected member order", members, expected)
+		}
+	}
+}
+
 func TestRectifyMappingAgainstHistory(t *testing.T) {
 	db := storage.NewMemoryBackedStateDB()
 	sto := storage.NewGeth(db, burn.NewSystemBurner(nil, false))
@@ -216,7 +216,7 @@ func initializeArbosAccount(_ *state.StateDB, arbosState *ArbosState, account st
 			if err != nil {
 				return err
 			}
-			err = poster.SetPayTo(account.AggregatorInfo.FeeCollector)
+			err = poster.SetPayTo(account.AggregatorInfo.FeeCollector, arbosState.ArbOSVersion())
 			if err != nil {
 				return err
 			}
@@ -83,8 +83,8 @@ func ApplyInternalTxUpdate(tx *types.ArbitrumInternalTx, state *arbosState.Arbos
 		currentTime := evm.Context.Time
 
 		// Try to reap 2 retryables
-		_ = state.RetryableState().TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM)
-		_ = state.RetryableState().TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM)
+		_ = state.RetryableState().TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM, state.ArbOSVersion())
+		_ = state.RetryableState().TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM, state.ArbOSVersion())
 
 		state.L2PricingState().UpdatePricingModel(l2BaseFee, timePassed, false)
 
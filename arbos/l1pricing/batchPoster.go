@@ -19,6 +19,7 @@ const totalFundsDueOffset = 0
 var (
 	PosterAddrsKey = []byte{0}
 	PosterInfoKey  = []byte{1}
+	PayToIndexKey  = []byte{2}
 
 	ErrAlreadyExists = errors.New("tried to add a batch poster that already exists")
 	ErrNotExist      = errors.New("tried to open a batch poster that does not exist")
@@ -28,10 +29,12 @@ var (
 type BatchPostersTable struct {
 	posterAddrs   *addressSet.AddressSet
 	posterInfo    *storage.Storage
+	payToIndex    *storage.Storage // pay-to address => the posters paying it, other than itself; from ArbOS 31
 	totalFundsDue storage.StorageBackedBigInt
 }
 
 type BatchPosterState struct {
+	address      common.Address
 	fundsDue     storage.StorageBackedBigInt
 	payTo        storage.StorageBackedAddress
 	postersTable *BatchPostersTable
@@ -49,6 +52,7 @@ func OpenBatchPostersTable(storage *storage.Storage) *BatchPostersTable {
 	return &BatchPostersTable{
 		posterAddrs:   addressSet.OpenAddressSet(storage.OpenCachedSubStorage(PosterAddrsKey)),
 		posterInfo:    storage.OpenSubStorage(PosterInfoKey),
+		payToIndex:    storage.OpenSubStorage(PayToIndexKey),
 		totalFundsDue: storage.OpenStorageBackedBigInt(totalFundsDueOffset),
 	}
 }
@@ -70,6 +74,7 @@ func (bpt *BatchPostersTable) OpenPoster(poster common.Address, createIfNotExist
 func (bpt *BatchPostersTable) internalOpen(poster common.Address) *BatchPosterState {
 	bpStorage := bpt.posterInfo.OpenSubStorage(poster.Bytes())
 	return &BatchPosterState{
+		address:      poster,
 		fundsDue:     bpStorage.OpenStorageBackedBigInt(0),
 		payTo:        bpStorage.OpenStorageBackedAddress(1),
 		postersTable: bpt,
@@ -95,6 +100,12 @@ func (bpt *BatchPostersTable) AddPoster(posterAddress common.Address, payTo comm
 	if err := bpState.payTo.Set(payTo); err != nil {
 		return nil, err
 	}
+	// posters paying themselves, as all those added on-chain do, are left out of the index
+	if payTo != posterAddress {
+		if err := bpt.payingTo(payTo).Add(posterAddress); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := bpt.posterAddrs.Add(posterAddress); err != nil {
 		return nil, err
@@ -136,10 +147,88 @@ func (bps *BatchPosterState) PayTo() (common.Address, error) {
 	return bps.payTo.Get()
 }
 
-func (bps *BatchPosterState) SetPayTo(addr common.Address) error {
+func (bps *BatchPosterState) SetPayTo(addr common.Address, arbosVersion uint64) error {
+	if arbosVersion >= 31 {
+		prev, err := bps.payTo.Get()
+		if err != nil {
+			return err
+		}
+		if prev != bps.address {
+			if err := bps.postersTable.payingTo(prev).Remove(bps.address, arbosVersion); err != nil {
+				return err
+			}
+		}
+		if addr != bps.address {
+			if err := bps.postersTable.payingTo(addr).Add(bps.address); err != nil {
+				return err
+			}
+		}
+	}
 	return bps.payTo.Set(addr)
 }
 
+// payingTo opens the set of posters that pay to the given address, other than the address itself
+func (bpt *BatchPostersTable) payingTo(payTo common.Address) *addressSet.AddressSet {
+	return addressSet.OpenAddressSet(bpt.payToIndex.OpenSubStorage(payTo.Bytes()))
+}
+
+// IndexPayTos adds every poster that pays someone else to the pay-to index, which is kept from ArbOS 31
+func (bpt *BatchPostersTable) IndexPayTos() error {
+	allPosters, err := bpt.AllPosters(math.MaxUint64)
+	if err != nil {
+		return err
+	}
+	for _, posterAddr := range allPosters {
+		payTo, err := bpt.internalOpen(posterAddr).PayTo()
+		if err != nil {
+			return err
+		}
+		if payTo != posterAddr {
+			if err := bpt.payingTo(payTo).Add(posterAddr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// paysItself checks whether the address is a batch poster that pays to itself
+func (bpt *BatchPostersTable) paysItself(account common.Address) (bool, error) {
+	isPoster, err := bpt.posterAddrs.IsMember(account)
+	if !isPoster || err != nil {
+		return false, err
+	}
+	payTo, err := bpt.internalOpen(account).PayTo()
+	return payTo == account, err
+}
+
+// HasPayTo checks whether any batch poster pays to the given address, using the index kept from ArbOS 31
+func (bpt *BatchPostersTable) HasPayTo(payTo common.Address) (bool, error) {
+	paysItself, err := bpt.paysItself(payTo)
+	if paysItself || err != nil {
+		return paysItself, err
+	}
+	size, err := bpt.payingTo(payTo).Size()
+	return size > 0, err
+}
+
+// PostersPayingTo gets the batch posters that pay to the given address, using the index kept from ArbOS 31.
+// The address itself comes first if it's a poster paying itself; the order of the rest is unspecified.
+func (bpt *BatchPostersTable) PostersPayingTo(payTo common.Address) ([]common.Address, error) {
+	others, err := bpt.payingTo(payTo).AllMembers(math.MaxUint64)
+	if err != nil {
+		return nil, err
+	}
+	paysItself, err := bpt.paysItself(payTo)
+	if err != nil {
+		return nil, err
+	}
+	if paysItself {
+		return append([]common.Address{payTo}, others...), nil
+	}
+	return others, nil
+}
+
 type FundsDueItem struct {
 	dueTo   common.Address
 	balance *big.Int
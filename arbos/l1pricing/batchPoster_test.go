@@ -83,13 +83,37 @@ func TestBatchPosterTable(t *testing.T) {
 	// test get/set of BP fields
 	bp1, err = bpTable.OpenPoster(addr1, false)
 	Require(t, err)
-	err = bp1.SetPayTo(addr2)
+	err = bp1.SetPayTo(addr2, 31)
 	Require(t, err)
 	getPay1, err = bp1.PayTo()
 	Require(t, err)
 	if getPay1 != addr2 {
 		t.Fatal()
 	}
+
+	// the reverse index follows the change, and counts a poster paying itself
+	paying, err := bpTable.PostersPayingTo(pay1)
+	Require(t, err)
+	if len(paying) != 0 {
+		t.Fatal(paying)
+	}
+	paying, err = bpTable.PostersPayingTo(addr2)
+	Require(t, err)
+	if len(paying) != 1 || paying[0] != addr1 {
+		t.Fatal(paying)
+	}
+	Require(t, bp2.SetPayTo(addr2, 31))
+	paying, err = bpTable.PostersPayingTo(addr2)
+	Require(t, err)
+	if len(paying) != 2 || paying[0] != addr2 || paying[1] != addr1 {
+		t.Fatal(paying)
+	}
+	hasPayTo, err := bpTable.HasPayTo(pay2)
+	Require(t, err)
+	if hasPayTo {
+		t.Fatal()
+	}
+	Require(t, bp2.SetPayTo(pay2, 31))
 	err = bp1.SetFundsDue(big.NewInt(13))
 	Require(t, err)
 	getDue1, err = bp1.FundsDue()
@@ -113,3 +137,34 @@ func TestBatchPosterTable(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestBatchPosterPayToIndexMigration(t *testing.T) {
+	sto := storage.NewMemoryBacked(burn.NewSystemBurner(nil, false))
+	Require(t, InitializeBatchPostersTable(sto))
+	bpTable := OpenBatchPostersTable(sto)
+
+	poster := common.Address{1, 2, 3}
+	payTo := common.Address{4, 5, 6, 7}
+	bp, err := bpTable.AddPoster(poster, poster)
+	Require(t, err)
+
+	// before ArbOS 31 changing the pay-to address leaves the index alone
+	Require(t, bp.SetPayTo(payTo, 30))
+	hasPayTo, err := bpTable.HasPayTo(payTo)
+	Require(t, err)
+	if hasPayTo {
+		t.Fatal("index was kept before ArbOS 31")
+	}
+
+	Require(t, bpTable.IndexPayTos())
+	paying, err := bpTable.PostersPayingTo(payTo)
+	Require(t, err)
+	if len(paying) != 1 || paying[0] != poster {
+		t.Fatal("migration didn't index the poster", paying)
+	}
+	paying, err = bpTable.PostersPayingTo(poster)
+	Require(t, err)
+	if len(paying) != 0 {
+		t.Fatal("poster no longer paying itself is still listed", paying)
+	}
+}
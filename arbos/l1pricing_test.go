@@ -160,7 +160,7 @@ func _testL1PricingFundsDue(t *testing.T, testParams *l1PricingTest, expectedRes
 	if due.Sign() != 0 {
 		Fail(t)
 	}
-	err = poster.SetPayTo(firstPayTo)
+	err = poster.SetPayTo(firstPayTo, arbosSt.ArbOSVersion())
 	Require(t, err)
 
 	// add another poster
@@ -4,6 +4,8 @@
 package arbos
 
 import (
+	"errors"
+	"math"
 	"math/big"
 	"math/rand"
 	"testing"
@@ -17,7 +19,9 @@ import (
 	"github.com/offchainlabs/nitro/util/testhelpers"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -51,7 +55,7 @@ func TestRetryableLifecycle(t *testing.T) {
 	proveReapingDoesNothing := func() {
 		stateCheck(t, statedb, false, "reaping had an effect", func() {
 			evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
-			Require(t, retryableState.TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM))
+			Require(t, retryableState.TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM, state.ArbOSVersion()))
 		})
 	}
 	checkQueueSize := func(expected int, message string) {
@@ -85,7 +89,7 @@ func TestRetryableLifecycle(t *testing.T) {
 	setTime((timestampAtCreation + timeoutAtCreation) / 2)
 	for _, id := range ids {
 		window := currentTime + lifetime
-		newTimeout, err := retryableState.Keepalive(id, currentTime, window, lifetime)
+		newTimeout, err := retryableState.Keepalive(id, currentTime, window, lifetime, state.ArbOSVersion())
 		Require(t, err, "failed to extend the retryable's lifetime")
 		proveReapingDoesNothing()
 		if newTimeout != timeoutAtCreation+lifetime {
@@ -93,7 +97,7 @@ func TestRetryableLifecycle(t *testing.T) {
 		}
 
 		// prove we need to wait before keepalive can succeed again
-		_, err = retryableState.Keepalive(id, currentTime, window, lifetime)
+		_, err = retryableState.Keepalive(id, currentTime, window, lifetime, state.ArbOSVersion())
 		if err == nil {
 			Fail(t, "keepalive should have failed")
 		}
@@ -107,7 +111,7 @@ func TestRetryableLifecycle(t *testing.T) {
 		// check that our reap pricing is reflective of the true cost
 		gasBefore := burner.Burned()
 		evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
-		Require(t, retryableState.TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM))
+		Require(t, retryableState.TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM, state.ArbOSVersion()))
 		gasBurnedToReap := burner.Burned() - gasBefore
 		if gasBurnedToReap != retryables.RetryableReapPrice {
 			Fail(t, "reaping has been mispriced", gasBurnedToReap, retryables.RetryableReapPrice)
@@ -123,13 +127,13 @@ func TestRetryableLifecycle(t *testing.T) {
 		shouldBeNil, err := retryableState.OpenRetryable(id, currentTime)
 		Require(t, err)
 		if shouldBeNil != nil {
-			timeout, _ := shouldBeNil.CalculateTimeout()
+			timeout, _ := shouldBeNil.CalculateTimeout(state.ArbOSVersion())
 			Fail(t, err, "read retryable after expiration", timeout, currentTime)
 		}
 
 		gasBefore := burner.Burned()
 		evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
-		Require(t, retryableState.TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM))
+		Require(t, retryableState.TryToReapOneRetryable(currentTime, evm, util.TracingDuringEVM, state.ArbOSVersion()))
 		gasBurnedToReapAndDelete := burner.Burned() - gasBefore
 		if gasBurnedToReapAndDelete <= retryables.RetryableReapPrice {
 			Fail(t, "deletion was cheap", gasBurnedToReapAndDelete, retryables.RetryableReapPrice)
@@ -139,7 +143,7 @@ func TestRetryableLifecycle(t *testing.T) {
 		shouldBeNil, err = retryableState.OpenRetryable(id, currentTime)
 		Require(t, err)
 		if shouldBeNil != nil {
-			timeout, _ := shouldBeNil.CalculateTimeout()
+			timeout, _ := shouldBeNil.CalculateTimeout(state.ArbOSVersion())
 			Fail(t, err, "read retryable after deletion", timeout, currentTime)
 		}
 	}
@@ -174,7 +178,7 @@ func TestRetryableCleanup(t *testing.T) {
 		_, err := retryableState.CreateRetryable(id, timeout, from, &to, callvalue, beneficiary, calldata)
 		Require(t, err)
 		evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
-		Require(t, retryableState.TryToReapOneRetryable(timestamp, evm, util.TracingDuringEVM))
+		Require(t, retryableState.TryToReapOneRetryable(timestamp, evm, util.TracingDuringEVM, state.ArbOSVersion()))
 		cleared, err := retryableState.TimeoutQueue.Shift()
 		Require(t, err)
 		if !cleared {
@@ -225,3 +229,554 @@ func stateCheck(t *testing.T, statedb *state.StateDB, change bool, message strin
 		Fail(t, message)
 	}
 }
+
+func TestRetryableRounding(t *testing.T) {
+	state, _ := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	policy, err := retryableState.RoundingPolicy()
+	Require(t, err)
+	if policy != 0 {
+		Fail(t, "unexpected default rounding policy", policy)
+	}
+	if err := retryableState.SetRoundingPolicy(1 << 1); !errors.Is(err, retryables.ErrInvalidRoundingPolicy) {
+		Fail(t, "expected invalid rounding policy error", err)
+	}
+
+	cases := []struct {
+		policy, value, divisor uint64
+		charge                 uint64
+	}{
+		{0, 0, 100, 0},
+		{0, 99, 100, 1},
+		{0, 100, 100, 1},
+		{0, 101, 100, 2},
+		{retryables.RoundChargesDown, 99, 100, 0},
+		{retryables.RoundChargesDown, 101, 100, 1},
+		{retryables.RoundChargesDown, 199, 100, 1},
+		{retryables.RoundChargesDown, 200, 100, 2},
+	}
+	for _, test := range cases {
+		Require(t, retryableState.SetRoundingPolicy(test.policy))
+		policy, err := retryableState.RoundingPolicy()
+		Require(t, err)
+		if charge := retryables.RoundCharge(policy, test.value, test.divisor); charge != test.charge {
+			Fail(t, "wrong charge", test.policy, test.value, charge, test.charge)
+		}
+		chargeBig := retryables.RoundChargeBig(policy, new(big.Int).SetUint64(test.value), test.divisor)
+		if chargeBig.Uint64() != test.charge {
+			Fail(t, "wrong big charge", test.policy, test.value, chargeBig, test.charge)
+		}
+	}
+}
+
+func TestRetryableBeneficiaryHistory(t *testing.T) {
+	state, statedb := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+	from := testhelpers.RandomAddress()
+	to := testhelpers.RandomAddress()
+	beneficiaries := []common.Address{testhelpers.RandomAddress()}
+	timeout := uint64(1 << 16)
+
+	stateCheck(t, statedb, false, "state has changed", func() {
+		retryable, err := retryableState.CreateRetryable(id, timeout, from, &to, big.NewInt(0), beneficiaries[0], nil)
+		Require(t, err)
+		for i := 0; i < retryables.MaxBeneficiaryHistory+3; i++ {
+			next := testhelpers.RandomAddress()
+			Require(t, retryable.SetBeneficiary(next))
+			beneficiaries = append(beneficiaries, next)
+		}
+		current, err := retryable.Beneficiary()
+		Require(t, err)
+		if current != beneficiaries[len(beneficiaries)-1] {
+			Fail(t, "wrong beneficiary", current)
+		}
+
+		history, err := retryable.BeneficiaryHistory()
+		Require(t, err)
+		expected := beneficiaries[len(beneficiaries)-1-retryables.MaxBeneficiaryHistory : len(beneficiaries)-1]
+		if len(history) != len(expected) {
+			Fail(t, "wrong history length", len(history), len(expected))
+		}
+		for i := range expected {
+			if history[i] != expected[i] {
+				Fail(t, "wrong history entry", i, history[i], expected[i])
+			}
+		}
+
+		evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
+		_, err = retryableState.DeleteRetryable(id, evm, util.TracingDuringEVM, 31)
+		Require(t, err)
+		_, err = retryableState.TimeoutQueue.Shift()
+		Require(t, err)
+	})
+}
+
+func TestRetryableRedeemHistory(t *testing.T) {
+	state, statedb := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+	from := testhelpers.RandomAddress()
+	to := testhelpers.RandomAddress()
+	timeout := uint64(1 << 16)
+
+	stateCheck(t, statedb, false, "state has changed", func() {
+		retryable, err := retryableState.CreateRetryable(id, timeout, from, &to, big.NewInt(0), from, nil)
+		Require(t, err)
+		redeemers := []common.Address{}
+		for i := 0; i < retryables.MaxRedeemHistory+3; i++ {
+			redeemer := testhelpers.RandomAddress()
+			nonce, err := retryable.IncrementNumTries()
+			Require(t, err)
+			Require(t, retryable.RecordRedeem(redeemer, nonce-1))
+			redeemers = append(redeemers, redeemer)
+		}
+
+		history, sequenceNums, err := retryable.RedeemHistory()
+		Require(t, err)
+		skipped := len(redeemers) - retryables.MaxRedeemHistory
+		if len(history) != retryables.MaxRedeemHistory || len(sequenceNums) != retryables.MaxRedeemHistory {
+			Fail(t, "wrong history length", len(history), len(sequenceNums))
+		}
+		for i := range history {
+			if history[i] != redeemers[skipped+i] || sequenceNums[i] != uint64(skipped+i) {
+				Fail(t, "wrong history entry", i, history[i], sequenceNums[i])
+			}
+		}
+
+		evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
+		_, err = retryableState.DeleteRetryable(id, evm, util.TracingDuringEVM, 31)
+		Require(t, err)
+		_, err = retryableState.TimeoutQueue.Shift()
+		Require(t, err)
+	})
+}
+
+func TestRetryableExportImport(t *testing.T) {
+	state, _ := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+	from := testhelpers.RandomAddress()
+	to := testhelpers.RandomAddress()
+	beneficiary := testhelpers.RandomAddress()
+	callvalue := big.NewInt(rand.Int63n(1 << 32))
+	calldata := testhelpers.RandomizeSlice(make([]byte, rand.Intn(1<<12)))
+	timeout := uint64(1 << 16)
+
+	retryable, err := retryableState.CreateRetryable(id, timeout, from, &to, callvalue, beneficiary, calldata)
+	Require(t, err)
+	_, err = retryable.IncrementNumTries()
+	Require(t, err)
+	_, err = retryableState.Keepalive(id, 0, timeout, retryables.RetryableLifetimeSeconds, 31)
+	Require(t, err)
+
+	exported, err := retryable.Export()
+	Require(t, err)
+
+	corrupted := append([]byte{}, exported...)
+	corrupted[0] ^= 1
+	if _, err := retryables.DecodeRetryableExport(corrupted); !errors.Is(err, retryables.ErrCorruptRetryableExport) {
+		Fail(t, "expected corrupt export error", err)
+	}
+
+	decoded, err := retryables.DecodeRetryableExport(exported)
+	Require(t, err)
+	if _, err := retryableState.ImportRetryable(decoded, 0); !errors.Is(err, retryables.ErrRetryableAlreadyExists) {
+		Fail(t, "expected import over an existing retryable to fail", err)
+	}
+
+	otherState, _ := arbosState.NewArbosMemoryBackedArbOSState()
+	imported, err := otherState.RetryableState().ImportRetryable(decoded, 0)
+	Require(t, err)
+	equal, err := retryable.Equals(imported)
+	Require(t, err)
+	if !equal {
+		Fail(t, "imported retryable differs from the original")
+	}
+	keepalives, err := imported.KeepaliveCount()
+	Require(t, err)
+	if keepalives != 1 {
+		Fail(t, "wrong keepalive count", keepalives)
+	}
+	queueSize, err := otherState.RetryableState().TimeoutQueue.Size()
+	Require(t, err)
+	if queueSize != 2 {
+		Fail(t, "wrong timeout queue size", queueSize)
+	}
+}
+
+func TestRetryableExpiryBuckets(t *testing.T) {
+	state, _ := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	now := uint64(1000)
+	lifetime := uint64(retryables.RetryableLifetimeSeconds)
+	timeouts := []uint64{now + 10, now + 20, now + 150, now + 30}
+	for i, timeout := range timeouts {
+		id := common.BigToHash(big.NewInt(int64(i + 1)))
+		to := testhelpers.RandomAddress()
+		_, err := retryableState.CreateRetryable(id, timeout, testhelpers.RandomAddress(), &to, big.NewInt(0), testhelpers.RandomAddress(), nil)
+		Require(t, err)
+	}
+	// extending the last retryable moves it out of range without double-counting it
+	_, err := retryableState.Keepalive(common.BigToHash(big.NewInt(4)), now, now+lifetime, lifetime, 31)
+	Require(t, err)
+
+	buckets, err := retryableState.ExpiryBuckets(now, 100, 3, math.MaxUint64, 31)
+	Require(t, err)
+	expected := []uint64{2, 1, 0}
+	if len(buckets) != len(expected) {
+		Fail(t, "wrong number of buckets", len(buckets))
+	}
+	for i := range expected {
+		if buckets[i] != expected[i] {
+			Fail(t, "wrong bucket count", i, buckets[i], expected[i])
+		}
+	}
+	// only the entries within the bound are counted
+	buckets, err = retryableState.ExpiryBuckets(now, 100, 3, 2, 31)
+	Require(t, err)
+	if buckets[0] != 2 || buckets[1] != 0 {
+		Fail(t, "counted entries past the bound", buckets)
+	}
+	if _, err := retryableState.ExpiryBuckets(now, 0, 3, math.MaxUint64, 31); err == nil {
+		Fail(t, "expected zero bucket size to fail")
+	}
+}
+
+func TestRetryableTimeoutIsHardCutoff(t *testing.T) {
+	state, _ := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+	to := testhelpers.RandomAddress()
+	timeout := uint64(1 << 16)
+	_, err := retryableState.CreateRetryable(id, timeout, testhelpers.RandomAddress(), &to, big.NewInt(0), testhelpers.RandomAddress(), nil)
+	Require(t, err)
+
+	// the retryable is live through its timeout and gone immediately after
+	retryable, err := retryableState.OpenRetryable(id, timeout)
+	Require(t, err)
+	if retryable == nil {
+		Fail(t, "retryable should be live at its timeout")
+	}
+	retryable, err = retryableState.OpenRetryable(id, timeout+1)
+	Require(t, err)
+	if retryable != nil {
+		Fail(t, "retryable should be expired after its timeout")
+	}
+}
+
+func TestRetryableConfigurableLifetime(t *testing.T) {
+	state, _ := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	lifetime, err := retryableState.Lifetime(31)
+	Require(t, err)
+	if lifetime != retryables.RetryableLifetimeSeconds {
+		Fail(t, "wrong default lifetime", lifetime)
+	}
+	newLifetime := uint64(60 * 60)
+	Require(t, retryableState.SetLifetime(newLifetime))
+	lifetime, err = retryableState.Lifetime(31)
+	Require(t, err)
+	if lifetime != newLifetime {
+		Fail(t, "wrong lifetime", lifetime)
+	}
+	lifetime, err = retryableState.Lifetime(30)
+	Require(t, err)
+	if lifetime != retryables.RetryableLifetimeSeconds {
+		Fail(t, "lifetime changed before ArbOS 31", lifetime)
+	}
+	if retryableState.SetLifetime(0) == nil {
+		Fail(t, "set a zero lifetime")
+	}
+	// a lifetime of k*2^57 would wrap the rent divisor to zero
+	if retryableState.SetLifetime(1<<57) == nil {
+		Fail(t, "set a huge lifetime")
+	}
+
+	id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+	from := testhelpers.RandomAddress()
+	to := testhelpers.RandomAddress()
+	timeout := newLifetime
+	_, err = retryableState.CreateRetryable(id, timeout, from, &to, big.NewInt(0), from, nil)
+	Require(t, err)
+	newTimeout, err := retryableState.Keepalive(id, 0, newLifetime, newLifetime, 31)
+	Require(t, err)
+	if newTimeout != timeout+newLifetime {
+		Fail(t, "keepalive didn't use the configured lifetime", newTimeout)
+	}
+}
+
+func TestRetryableLifetimeChangeKeepsBoughtWindows(t *testing.T) {
+	state, statedb := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+	from := testhelpers.RandomAddress()
+	now := uint64(1000)
+	oldLifetime := uint64(retryables.RetryableLifetimeSeconds)
+	timeout := now + 100
+	_, err := retryableState.CreateRetryable(id, timeout, from, &from, big.NewInt(0), from, nil)
+	Require(t, err)
+	_, err = retryableState.Keepalive(id, now, math.MaxUint64, oldLifetime, 31)
+	Require(t, err)
+
+	// shortening the lifetime mustn't shorten the window the ticket already paid for
+	newLifetime := uint64(60 * 60)
+	Require(t, retryableState.SetLifetime(newLifetime))
+	retryable, err := retryableState.OpenRetryable(id, now)
+	Require(t, err)
+	calculated, err := retryable.CalculateTimeout(31)
+	Require(t, err)
+	if calculated != timeout+oldLifetime {
+		Fail(t, "lifetime change altered a bought window", calculated, timeout+oldLifetime)
+	}
+
+	// while windows remain, further keepalives buy windows of the same length
+	newTimeout, err := retryableState.Keepalive(id, now, math.MaxUint64, newLifetime, 31)
+	Require(t, err)
+	if newTimeout != timeout+2*oldLifetime {
+		Fail(t, "keepalive mixed window lengths", newTimeout, timeout+2*oldLifetime)
+	}
+
+	// reaping consumes a window by the length it was bought with
+	evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
+	Require(t, retryableState.TryToReapOneRetryable(timeout+1, evm, util.TracingDuringEVM, 31))
+	current, err := retryable.Timeout()
+	Require(t, err)
+	if current != timeout+oldLifetime {
+		Fail(t, "reaping used the new lifetime", current, timeout+oldLifetime)
+	}
+	calculated, err = retryable.CalculateTimeout(31)
+	Require(t, err)
+	if calculated != newTimeout {
+		Fail(t, "reaping changed the expiry", calculated, newTimeout)
+	}
+}
+
+func TestRetryableMinKeepaliveInterval(t *testing.T) {
+	state, _ := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+	from := testhelpers.RandomAddress()
+	lifetime := uint64(retryables.RetryableLifetimeSeconds)
+	now := uint64(1000)
+	_, err := retryableState.CreateRetryable(id, now+lifetime, from, &from, big.NewInt(0), from, nil)
+	Require(t, err)
+
+	keepalive := func(now uint64) error {
+		_, err := retryableState.Keepalive(id, now, now+10*lifetime, lifetime, 31)
+		return err
+	}
+
+	// back-to-back keepalives are fine until a minimum is set
+	Require(t, keepalive(now))
+	Require(t, keepalive(now))
+
+	interval := uint64(60)
+	Require(t, retryableState.SetMinKeepaliveInterval(interval))
+	if keepalive(now+interval-1) == nil {
+		Fail(t, "keepalive allowed before the minimum interval")
+	}
+	Require(t, keepalive(now+interval))
+}
+
+func TestRetryableSweepExpired(t *testing.T) {
+	state, statedb := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+
+	now := uint64(1 << 20)
+	var expired, live []common.Hash
+	for i := 0; i < 5; i++ {
+		id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+		from := testhelpers.RandomAddress()
+		timeout := now - 100 + uint64(i)
+		if i >= 3 {
+			timeout = now + 100
+			live = append(live, id)
+		} else {
+			expired = append(expired, id)
+		}
+		_, err := retryableState.CreateRetryable(id, timeout, from, &from, big.NewInt(0), from, nil)
+		Require(t, err)
+	}
+
+	evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
+	sweep := func(maxToDelete int) int {
+		deleted, err := retryableState.SweepExpired(maxToDelete, now, evm, util.TracingDuringEVM, state.ArbOSVersion())
+		Require(t, err)
+		return deleted
+	}
+	if deleted := sweep(2); deleted != 2 {
+		Fail(t, "sweep ignored its limit", deleted)
+	}
+	if deleted := sweep(10); deleted != 1 {
+		Fail(t, "wrong number swept", deleted)
+	}
+
+	for _, id := range expired {
+		retryable, err := retryableState.OpenRetryable(id, 0)
+		Require(t, err)
+		if retryable != nil {
+			Fail(t, "expired retryable survived the sweep", id)
+		}
+	}
+	for _, id := range live {
+		retryable, err := retryableState.OpenRetryable(id, now)
+		Require(t, err)
+		if retryable == nil {
+			Fail(t, "live retryable was swept", id)
+		}
+	}
+}
+
+func TestRetryableTotalStorageBytes(t *testing.T) {
+	state, statedb := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+	version := uint64(31)
+	evm := vm.NewEVM(vm.BlockContext{}, vm.TxContext{}, statedb, &params.ChainConfig{}, vm.Config{})
+
+	checkTotal := func(ids []common.Hash) {
+		t.Helper()
+		expected := uint64(0)
+		for _, id := range ids {
+			size, err := retryableState.RetryableSizeBytes(id, 0)
+			Require(t, err)
+			expected += size
+		}
+		total, err := retryableState.TotalStorageBytes()
+		Require(t, err)
+		if total != expected {
+			Fail(t, "wrong storage total", total, expected)
+		}
+	}
+
+	var ids []common.Hash
+	for i := 0; i < 4; i++ {
+		id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+		from := testhelpers.RandomAddress()
+		retryable, err := retryableState.CreateRetryable(id, 1000, from, &from, big.NewInt(0), from, make([]byte, 50*i))
+		Require(t, err)
+		Require(t, retryableState.TrackStorage(retryable))
+		ids = append(ids, id)
+	}
+	checkTotal(ids)
+
+	_, err := retryableState.DeleteRetryable(ids[1], evm, util.TracingDuringEVM, version)
+	Require(t, err)
+	ids = append(ids[:1], ids[2:]...)
+	checkTotal(ids)
+
+	// expiry sweeps delete through the same path
+	deleted, err := retryableState.SweepExpired(len(ids), 1001, evm, util.TracingDuringEVM, version)
+	Require(t, err)
+	if deleted != len(ids) {
+		Fail(t, "wrong number swept", deleted)
+	}
+	checkTotal(nil)
+}
+
+func TestValidateSubmissionAmounts(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 256), common.Big1)
+	tooBig := new(big.Int).Lsh(common.Big1, 256)
+	zero := common.Big0
+
+	Require(t, retryables.ValidateSubmissionAmounts(maxUint256, maxUint256, zero, maxUint256))
+	Require(t, retryables.ValidateSubmissionAmounts(maxUint256, zero, maxUint256, zero))
+	Require(t, retryables.ValidateSubmissionAmounts(maxUint256, big.NewInt(1), new(big.Int).Sub(maxUint256, common.Big1), zero))
+
+	invalid := [][4]*big.Int{
+		{tooBig, zero, zero, zero},
+		{zero, big.NewInt(-1), zero, zero},
+		{zero, zero, big.NewInt(-1), zero},
+		{zero, zero, zero, tooBig},
+		{zero, zero, zero, nil},
+		// these sums would wrap to a small value in 256-bit arithmetic
+		{maxUint256, maxUint256, big.NewInt(1), zero},
+		{maxUint256, big.NewInt(2), maxUint256, zero},
+	}
+	for i, amounts := range invalid {
+		if retryables.ValidateSubmissionAmounts(amounts[0], amounts[1], amounts[2], amounts[3]) == nil {
+			Fail(t, "submission amounts should be rejected", i)
+		}
+	}
+}
+
+func TestSubmitRetryableRejectsInvalidDeposit(t *testing.T) {
+	chainConfig := params.ArbitrumDevTestChainConfig()
+	for _, deposit := range []*big.Int{big.NewInt(-1), new(big.Int).Lsh(common.Big1, 256)} {
+		state, statedb := arbosState.NewArbosMemoryBackedArbOSState()
+		if state.ArbOSVersion() < 31 {
+			Require(t, state.UpgradeArbosVersion(31, false, statedb, chainConfig))
+		}
+		evm := vm.NewEVM(vm.BlockContext{BlockNumber: big.NewInt(0)}, vm.TxContext{}, statedb, chainConfig, vm.Config{})
+
+		from := testhelpers.RandomAddress()
+		to := testhelpers.RandomAddress()
+		tx := types.NewTx(&types.ArbitrumSubmitRetryableTx{
+			ChainId:          chainConfig.ChainID,
+			RequestId:        common.BigToHash(big.NewInt(rand.Int63n(1 << 32))),
+			From:             from,
+			L1BaseFee:        big.NewInt(0),
+			DepositValue:     deposit,
+			GasFeeCap:        big.NewInt(0),
+			RetryTo:          &to,
+			RetryValue:       big.NewInt(0),
+			Beneficiary:      from,
+			MaxSubmissionFee: big.NewInt(0),
+			FeeRefundAddr:    from,
+			RetryData:        []byte{},
+		})
+		retryableAddress := types.ArbRetryableTxAddress
+		processor := NewTxProcessor(evm, &core.Message{
+			Tx:        tx,
+			From:      from,
+			To:        &retryableAddress,
+			TxRunMode: core.MessageCommitMode,
+		})
+
+		// this would panic if the deposit were minted before being validated
+		endTxNow, _, err, _ := processor.StartTxHook()
+		if !endTxNow || err == nil {
+			Fail(t, "accepted an out-of-range deposit", deposit)
+		}
+		if balance := statedb.GetBalance(from); !balance.IsZero() {
+			Fail(t, "minted an out-of-range deposit", deposit, balance)
+		}
+	}
+}
+
+func TestRetryableHugeLifetimeSaturates(t *testing.T) {
+	state, _ := arbosState.NewArbosMemoryBackedArbOSState()
+	retryableState := state.RetryableState()
+	Require(t, retryableState.SetLifetime(retryables.MaxRetryableLifetimeSeconds))
+
+	id := common.BigToHash(big.NewInt(rand.Int63n(1 << 32)))
+	from := testhelpers.RandomAddress()
+	// importing is the simplest way to give a ticket windows left
+	retryable, err := retryableState.ImportRetryable(&retryables.RetryableExport{
+		Id:                 id,
+		From:               from,
+		To:                 &from,
+		Callvalue:          big.NewInt(0),
+		Beneficiary:        from,
+		Timeout:            math.MaxUint64 - 1,
+		TimeoutWindowsLeft: 3,
+		WindowLifetime:     retryables.MaxRetryableLifetimeSeconds,
+	}, 0)
+	Require(t, err)
+
+	// the timeout saturates rather than wrapping into the past
+	timeout, err := retryable.CalculateTimeout(31)
+	Require(t, err)
+	if timeout != math.MaxUint64 {
+		Fail(t, "timeout wrapped", timeout)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package retryables
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	ErrCorruptRetryableExport = errors.New("retryable export failed its integrity check")
+	ErrRetryableAlreadyExists = errors.New("retryable already exists")
+)
+
+// RetryableExport is the canonical form of a retryable's state used to migrate it between chains
+type RetryableExport struct {
+	Id                 common.Hash
+	NumTries           uint64
+	From               common.Address
+	To                 *common.Address `rlp:"nil"`
+	Callvalue          *big.Int
+	Beneficiary        common.Address
+	Timeout            uint64
+	TimeoutWindowsLeft uint64
+	WindowLifetime     uint64 // zero for windows bought before ArbOS 31
+	AutoRedeemed       bool
+	InitialTimeout     uint64
+	KeepaliveCount     uint64
+	Calldata           []byte
+}
+
+// Export serializes the retryable's state, appending a keccak checksum of the encoding
+func (retryable *Retryable) Export() ([]byte, error) {
+	numTries, err := retryable.NumTries()
+	if err != nil {
+		return nil, err
+	}
+	from, err := retryable.From()
+	if err != nil {
+		return nil, err
+	}
+	to, err := retryable.To()
+	if err != nil {
+		return nil, err
+	}
+	callvalue, err := retryable.Callvalue()
+	if err != nil {
+		return nil, err
+	}
+	beneficiary, err := retryable.Beneficiary()
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := retryable.timeout.Get()
+	if err != nil {
+		return nil, err
+	}
+	windows, err := retryable.TimeoutWindowsLeft()
+	if err != nil {
+		return nil, err
+	}
+	windowLifetime, err := retryable.windowLifetime.Get()
+	if err != nil {
+		return nil, err
+	}
+	autoRedeemed, err := retryable.WasAutoRedeemed()
+	if err != nil {
+		return nil, err
+	}
+	initialTimeout, err := retryable.InitialTimeout()
+	if err != nil {
+		return nil, err
+	}
+	keepalives, err := retryable.KeepaliveCount()
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := retryable.Calldata()
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := rlp.EncodeToBytes(&RetryableExport{
+		Id:                 retryable.id,
+		NumTries:           numTries,
+		From:               from,
+		To:                 to,
+		Callvalue:          callvalue,
+		Beneficiary:        beneficiary,
+		Timeout:            timeout,
+		TimeoutWindowsLeft: windows,
+		WindowLifetime:     windowLifetime,
+		AutoRedeemed:       autoRedeemed,
+		InitialTimeout:     initialTimeout,
+		KeepaliveCount:     keepalives,
+		Calldata:           calldata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(encoded, crypto.Keccak256(encoded)...), nil
+}
+
+// DecodeRetryableExport verifies an exported retryable's checksum and decodes it
+func DecodeRetryableExport(data []byte) (*RetryableExport, error) {
+	if len(data) < common.HashLength {
+		return nil, ErrCorruptRetryableExport
+	}
+	encoded, checksum := data[:len(data)-common.HashLength], data[len(data)-common.HashLength:]
+	if !bytes.Equal(crypto.Keccak256(encoded), checksum) {
+		return nil, ErrCorruptRetryableExport
+	}
+	export := &RetryableExport{}
+	if err := rlp.DecodeBytes(encoded, export); err != nil {
+		return nil, err
+	}
+	if export.Timeout == 0 || export.Callvalue == nil || export.WindowLifetime > MaxRetryableLifetimeSeconds {
+		return nil, ErrCorruptRetryableExport
+	}
+	return export, nil
+}
+
+// ImportRetryable recreates an exported retryable under the same ticket id.
+// The caller is responsible for ensuring the ticket's escrow holds its callvalue.
+func (rs *RetryableState) ImportRetryable(export *RetryableExport, currentTimestamp uint64) (*Retryable, error) {
+	existing, err := rs.OpenRetryable(export.Id, 0)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrRetryableAlreadyExists
+	}
+	if export.Timeout < currentTimestamp {
+		return nil, errors.New("imported retryable has already expired")
+	}
+	retryable, err := rs.CreateRetryable(
+		export.Id,
+		export.Timeout,
+		export.From,
+		export.To,
+		export.Callvalue,
+		export.Beneficiary,
+		export.Calldata,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := retryable.numTries.Set(export.NumTries); err != nil {
+		return nil, err
+	}
+	if export.AutoRedeemed {
+		if err := retryable.SetAutoRedeemed(); err != nil {
+			return nil, err
+		}
+	}
+	if err := retryable.SetInitialTimeout(export.InitialTimeout); err != nil {
+		return nil, err
+	}
+	if err := retryable.keepaliveCount.Set(export.KeepaliveCount); err != nil {
+		return nil, err
+	}
+
+	// each remaining window needs its own queue entry, as if it were added by a keepalive
+	for i := uint64(0); i < export.TimeoutWindowsLeft; i++ {
+		if err := rs.TimeoutQueue.Put(export.Id); err != nil {
+			return nil, err
+		}
+	}
+	if err := retryable.timeoutWindowsLeft.Set(export.TimeoutWindowsLeft); err != nil {
+		return nil, err
+	}
+	if err := retryable.windowLifetime.Set(export.WindowLifetime); err != nil {
+		return nil, err
+	}
+	return retryable, rs.TrackStorage(retryable)
+}
@@ -5,29 +5,93 @@ package retryables
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"math/big"
+	"math/bits"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/offchainlabs/nitro/arbos/addressSet"
 	"github.com/offchainlabs/nitro/arbos/storage"
 	"github.com/offchainlabs/nitro/arbos/util"
 	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
 const RetryableLifetimeSeconds = 7 * 24 * 60 * 60 // one week
+const MaxRetryableLifetimeSeconds = 1 << 32
 const RetryableReapPrice = 58000
+const MaxBeneficiaryHistory = 8
+const MaxRedeemHistory = 16
+const MaxExpiryBuckets = 1024
 
 type RetryableState struct {
-	retryables   *storage.Storage
-	TimeoutQueue *storage.Queue
+	retryables          *storage.Storage
+	TimeoutQueue        *storage.Queue
+	totalRentCollected  storage.StorageBackedBigUint
+	roundingPolicy      storage.StorageBackedUint64
+	maxRedeemGas        storage.StorageBackedUint64
+	maxTries            storage.StorageBackedUint64
+	maxTriesPolicy      storage.StorageBackedUint64
+	manualRedeemOnly    *addressSet.AddressSet
+	submissionCounts    *storage.Storage
+	retryablesCreated   storage.StorageBackedUint64
+	redeemsScheduled    storage.StorageBackedUint64
+	lifetime            storage.StorageBackedUint64
+	minKeepalive        storage.StorageBackedUint64
+	cancelGracePeriod   storage.StorageBackedUint64
+	redeemChargePerWord storage.StorageBackedUint64
+	minRedeemDonation   storage.StorageBackedUint64
+	totalStorageBytes   storage.StorageBackedUint64
+	retryablesCancelled storage.StorageBackedUint64
+	retryablesExpired   storage.StorageBackedUint64
 }
 
 var (
-	timeoutQueueKey = []byte{0}
-	calldataKey     = []byte{1}
+	timeoutQueueKey       = []byte{0}
+	calldataKey           = []byte{1}
+	beneficiaryHistoryKey = []byte{2}
+	manualRedeemOnlyKey   = []byte{3}
+	submissionCountsKey   = []byte{4}
+	redeemHistoryKey      = []byte{5}
+)
+
+const (
+	totalRentCollectedOffset uint64 = iota
+	roundingPolicyOffset
+	maxRedeemGasOffset
+	maxTriesOffset
+	maxTriesPolicyOffset
+	retryablesCreatedOffset
+	redeemsScheduledOffset
+	lifetimeOffset
+	minKeepaliveOffset
+	cancelGracePeriodOffset
+	redeemChargePerWordOffset
+	minRedeemDonationOffset
+	totalStorageBytesOffset
+	retryablesCancelledOffset
+	retryablesExpiredOffset
+)
+
+// Rounding policy flags for rent charges.
+// The zero policy is protocol-favorable, rounding charges up. Rent is never refunded, so there's no refund rounding.
+const (
+	RoundChargesDown uint64 = 1 << iota
+
+	roundingPolicyMask = RoundChargesDown
+)
+
+var ErrInvalidRoundingPolicy = errors.New("invalid retryable rounding policy")
+
+// Policies for redeeming a retryable that has reached the maximum number of tries
+const (
+	MaxTriesRevert uint64 = iota // reject the redeem
+	MaxTriesCancel               // cancel the retryable, refunding its escrow to the beneficiary
 )
 
 func InitializeRetryableState(sto *storage.Storage) error {
@@ -38,7 +102,237 @@ func OpenRetryableState(sto *storage.Storage, statedb vm.StateDB) *RetryableStat
 	return &RetryableState{
 		sto,
 		storage.OpenQueue(sto.OpenCachedSubStorage(timeoutQueueKey)),
+		sto.OpenStorageBackedBigUint(totalRentCollectedOffset),
+		sto.OpenStorageBackedUint64(roundingPolicyOffset),
+		sto.OpenStorageBackedUint64(maxRedeemGasOffset),
+		sto.OpenStorageBackedUint64(maxTriesOffset),
+		sto.OpenStorageBackedUint64(maxTriesPolicyOffset),
+		addressSet.OpenAddressSet(sto.OpenCachedSubStorage(manualRedeemOnlyKey)),
+		sto.OpenSubStorage(submissionCountsKey),
+		sto.OpenStorageBackedUint64(retryablesCreatedOffset),
+		sto.OpenStorageBackedUint64(redeemsScheduledOffset),
+		sto.OpenStorageBackedUint64(lifetimeOffset),
+		sto.OpenStorageBackedUint64(minKeepaliveOffset),
+		sto.OpenStorageBackedUint64(cancelGracePeriodOffset),
+		sto.OpenStorageBackedUint64(redeemChargePerWordOffset),
+		sto.OpenStorageBackedUint64(minRedeemDonationOffset),
+		sto.OpenStorageBackedUint64(totalStorageBytesOffset),
+		sto.OpenStorageBackedUint64(retryablesCancelledOffset),
+		sto.OpenStorageBackedUint64(retryablesExpiredOffset),
+	}
+}
+
+// MinKeepaliveInterval gets the fewest seconds allowed between a ticket's keepalives, or 0 if unrestricted
+func (rs *RetryableState) MinKeepaliveInterval() (uint64, error) {
+	return rs.minKeepalive.Get()
+}
+
+func (rs *RetryableState) SetMinKeepaliveInterval(interval uint64) error {
+	return rs.minKeepalive.Set(interval)
+}
+
+// TotalStorageBytes gets the bytes of state held by live retryables created since ArbOS 31
+func (rs *RetryableState) TotalStorageBytes() (uint64, error) {
+	return rs.totalStorageBytes.Get()
+}
+
+// TrackStorage adds a newly created retryable's size to the running total
+func (rs *RetryableState) TrackStorage(retryable *Retryable) error {
+	size, err := retryable.SizeBytes()
+	if err != nil {
+		return err
+	}
+	total, err := rs.totalStorageBytes.Get()
+	if err != nil {
+		return err
+	}
+	return rs.totalStorageBytes.Set(arbmath.SaturatingUAdd(total, size))
+}
+
+// MinRedeemDonation gets the least gas a manual redeem may donate to its retry, or 0 if there's no floor
+func (rs *RetryableState) MinRedeemDonation() (uint64, error) {
+	return rs.minRedeemDonation.Get()
+}
+
+func (rs *RetryableState) SetMinRedeemDonation(gas uint64) error {
+	return rs.minRedeemDonation.Set(gas)
+}
+
+// RedeemChargePerWord gets the gas a redeem burns per word of the ticket's size, on top of the gas it donates.
+// This is configurable from ArbOS 31, and defaults to the cost of a storage read.
+func (rs *RetryableState) RedeemChargePerWord(arbosVersion uint64) (uint64, error) {
+	if arbosVersion < 31 {
+		return params.SloadGas, nil
+	}
+	charge, err := rs.redeemChargePerWord.Get()
+	if err != nil || charge != 0 {
+		return charge, err
+	}
+	return params.SloadGas, nil
+}
+
+func (rs *RetryableState) SetRedeemChargePerWord(charge uint64) error {
+	return rs.redeemChargePerWord.Set(charge)
+}
+
+// CancelGracePeriod gets the seconds after scheduling a redeem during which the ticket can't be canceled
+// while the redeem is pending, or 0 if a pending redeem holds off cancellation until it executes
+func (rs *RetryableState) CancelGracePeriod() (uint64, error) {
+	return rs.cancelGracePeriod.Get()
+}
+
+func (rs *RetryableState) SetCancelGracePeriod(period uint64) error {
+	return rs.cancelGracePeriod.Set(period)
+}
+
+// Lifetime gets the length of a retryable's lifetime period, which is configurable from ArbOS 31
+func (rs *RetryableState) Lifetime(arbosVersion uint64) (uint64, error) {
+	if arbosVersion < 31 {
+		return RetryableLifetimeSeconds, nil
+	}
+	lifetime, err := rs.lifetime.Get()
+	if err != nil || lifetime != 0 {
+		return lifetime, err
+	}
+	return RetryableLifetimeSeconds, nil
+}
+
+func (rs *RetryableState) SetLifetime(lifetime uint64) error {
+	if lifetime == 0 {
+		return errors.New("retryable lifetime must be nonzero")
+	}
+	if lifetime > MaxRetryableLifetimeSeconds {
+		return fmt.Errorf("retryable lifetime %v exceeds the maximum of %v seconds", lifetime, MaxRetryableLifetimeSeconds)
+	}
+	return rs.lifetime.Set(lifetime)
+}
+
+// RetryablesCreated gets the number of retryables submitted since the counter was introduced in ArbOS 31
+func (rs *RetryableState) RetryablesCreated() (uint64, error) {
+	return rs.retryablesCreated.Get()
+}
+
+func (rs *RetryableState) IncrementRetryablesCreated() error {
+	_, err := rs.retryablesCreated.Increment()
+	return err
+}
+
+// RedeemsScheduled gets the number of redeems scheduled since the counter was introduced in ArbOS 31
+func (rs *RetryableState) RedeemsScheduled() (uint64, error) {
+	return rs.redeemsScheduled.Get()
+}
+
+func (rs *RetryableState) IncrementRedeemsScheduled() error {
+	_, err := rs.redeemsScheduled.Increment()
+	return err
+}
+
+// RetryablesCancelled gets the number of retryables cancelled by their beneficiaries since ArbOS 31
+func (rs *RetryableState) RetryablesCancelled() (uint64, error) {
+	return rs.retryablesCancelled.Get()
+}
+
+func (rs *RetryableState) IncrementRetryablesCancelled() error {
+	_, err := rs.retryablesCancelled.Increment()
+	return err
+}
+
+// RetryablesExpired gets the number of expired retryables deleted since ArbOS 31
+func (rs *RetryableState) RetryablesExpired() (uint64, error) {
+	return rs.retryablesExpired.Get()
+}
+
+func (rs *RetryableState) IncrementRetryablesExpired() error {
+	_, err := rs.retryablesExpired.Increment()
+	return err
+}
+
+// SubmissionCount gets the number of retryables created with the given sender
+func (rs *RetryableState) SubmissionCount(sender common.Address) (uint64, error) {
+	return rs.submissionCounts.GetUint64(util.AddressToHash(sender))
+}
+
+func (rs *RetryableState) IncrementSubmissionCount(sender common.Address) error {
+	key := util.AddressToHash(sender)
+	count, err := rs.submissionCounts.GetUint64(key)
+	if err != nil {
+		return err
+	}
+	return rs.submissionCounts.SetUint64(key, count+1)
+}
+
+// ManualRedeemOnly gets the set of destinations whose retryables are never auto-redeemed at submission
+func (rs *RetryableState) ManualRedeemOnly() *addressSet.AddressSet {
+	return rs.manualRedeemOnly
+}
+
+func (rs *RetryableState) RoundingPolicy() (uint64, error) {
+	return rs.roundingPolicy.Get()
+}
+
+func (rs *RetryableState) SetRoundingPolicy(policy uint64) error {
+	if policy&^roundingPolicyMask != 0 {
+		return ErrInvalidRoundingPolicy
+	}
+	return rs.roundingPolicy.Set(policy)
+}
+
+// MaxRedeemGas gets the most gas a manual redeem may donate to its retry, or 0 if uncapped
+func (rs *RetryableState) MaxRedeemGas() (uint64, error) {
+	return rs.maxRedeemGas.Get()
+}
+
+func (rs *RetryableState) SetMaxRedeemGas(gas uint64) error {
+	return rs.maxRedeemGas.Set(gas)
+}
+
+// MaxTries gets the number of redeem attempts a retryable may have, or 0 if unlimited
+func (rs *RetryableState) MaxTries() (uint64, error) {
+	return rs.maxTries.Get()
+}
+
+func (rs *RetryableState) SetMaxTries(tries uint64) error {
+	return rs.maxTries.Set(tries)
+}
+
+func (rs *RetryableState) MaxTriesPolicy() (uint64, error) {
+	return rs.maxTriesPolicy.Get()
+}
+
+func (rs *RetryableState) SetMaxTriesPolicy(policy uint64) error {
+	if policy > MaxTriesCancel {
+		return errors.New("invalid max tries policy")
 	}
+	return rs.maxTriesPolicy.Set(policy)
+}
+
+// RoundCharge divides a charge according to the rounding policy
+func RoundCharge(policy, value, divisor uint64) uint64 {
+	if policy&RoundChargesDown != 0 {
+		return value / divisor
+	}
+	return arbmath.DivCeil(value, divisor)
+}
+
+// RoundChargeBig divides a wei-denominated charge according to the rounding policy
+func RoundChargeBig(policy uint64, value *big.Int, divisor uint64) *big.Int {
+	if policy&RoundChargesDown != 0 {
+		return arbmath.BigDivByUint(value, divisor)
+	}
+	return arbmath.BigDivByUint(arbmath.BigAddByUint(value, divisor-1), divisor)
+}
+
+// TotalRentCollected gets the cumulative wei charged for extending retryable lifetimes
+func (rs *RetryableState) TotalRentCollected() (*big.Int, error) {
+	return rs.totalRentCollected.Get()
+}
+
+func (rs *RetryableState) AddRentCollected(amount *big.Int) error {
+	total, err := rs.totalRentCollected.Get()
+	if err != nil {
+		return err
+	}
+	return rs.totalRentCollected.SetSaturatingWithWarning(arbmath.BigAdd(total, amount), "total retryable rent collected")
 }
 
 type Retryable struct {
@@ -52,6 +346,13 @@ type Retryable struct {
 	calldata           storage.StorageBackedBytes
 	timeout            storage.StorageBackedUint64
 	timeoutWindowsLeft storage.StorageBackedUint64
+	autoRedeemed       storage.StorageBackedUint64
+	initialTimeout     storage.StorageBackedUint64
+	keepaliveCount     storage.StorageBackedUint64
+	lastKeepalive      storage.StorageBackedUint64
+	pendingRedeems     storage.StorageBackedUint64
+	lastRedeemTime     storage.StorageBackedUint64
+	windowLifetime     storage.StorageBackedUint64
 }
 
 const (
@@ -62,6 +363,13 @@ const (
 	beneficiaryOffset
 	timeoutOffset
 	timeoutWindowsLeftOffset
+	autoRedeemedOffset
+	initialTimeoutOffset
+	keepaliveCountOffset
+	lastKeepaliveOffset
+	pendingRedeemsOffset
+	lastRedeemTimeOffset
+	windowLifetimeOffset
 )
 
 func (rs *RetryableState) CreateRetryable(
@@ -85,6 +393,13 @@ func (rs *RetryableState) CreateRetryable(
 		sto.OpenStorageBackedBytes(calldataKey),
 		sto.OpenStorageBackedUint64(timeoutOffset),
 		sto.OpenStorageBackedUint64(timeoutWindowsLeftOffset),
+		sto.OpenStorageBackedUint64(autoRedeemedOffset),
+		sto.OpenStorageBackedUint64(initialTimeoutOffset),
+		sto.OpenStorageBackedUint64(keepaliveCountOffset),
+		sto.OpenStorageBackedUint64(lastKeepaliveOffset),
+		sto.OpenStorageBackedUint64(pendingRedeemsOffset),
+		sto.OpenStorageBackedUint64(lastRedeemTimeOffset),
+		sto.OpenStorageBackedUint64(windowLifetimeOffset),
 	}
 	_ = ret.numTries.Set(0)
 	_ = ret.from.Set(from)
@@ -120,6 +435,13 @@ func (rs *RetryableState) OpenRetryable(id common.Hash, currentTimestamp uint64)
 		calldata:           sto.OpenStorageBackedBytes(calldataKey),
 		timeout:            timeoutStorage,
 		timeoutWindowsLeft: sto.OpenStorageBackedUint64(timeoutWindowsLeftOffset),
+		autoRedeemed:       sto.OpenStorageBackedUint64(autoRedeemedOffset),
+		initialTimeout:     sto.OpenStorageBackedUint64(initialTimeoutOffset),
+		keepaliveCount:     sto.OpenStorageBackedUint64(keepaliveCountOffset),
+		lastKeepalive:      sto.OpenStorageBackedUint64(lastKeepaliveOffset),
+		pendingRedeems:     sto.OpenStorageBackedUint64(pendingRedeemsOffset),
+		lastRedeemTime:     sto.OpenStorageBackedUint64(lastRedeemTimeOffset),
+		windowLifetime:     sto.OpenStorageBackedUint64(windowLifetimeOffset),
 	}, nil
 }
 
@@ -128,12 +450,21 @@ func (rs *RetryableState) RetryableSizeBytes(id common.Hash, currentTime uint64)
 	if retryable == nil || err != nil {
 		return 0, err
 	}
+	return retryable.SizeBytes()
+}
+
+// SizeBytes gets the number of bytes of state the retryable occupies
+func (retryable *Retryable) SizeBytes() (uint64, error) {
 	size, err := retryable.CalldataSize()
-	calldata := 32 + 32*arbmath.WordsForBytes(size) // length + contents
-	return 6*32 + calldata, err
+	return sizeBytes(size), err
 }
 
-func (rs *RetryableState) DeleteRetryable(id common.Hash, evm *vm.EVM, scenario util.TracingScenario) (bool, error) {
+func sizeBytes(calldataSize uint64) uint64 {
+	calldata := 32 + 32*arbmath.WordsForBytes(calldataSize) // length + contents
+	return 6*32 + calldata
+}
+
+func (rs *RetryableState) DeleteRetryable(id common.Hash, evm *vm.EVM, scenario util.TracingScenario, arbosVersion uint64) (bool, error) {
 	retStorage := rs.retryables.OpenSubStorage(id.Bytes())
 	timeout, err := retStorage.GetByUint64(timeoutOffset)
 	if timeout == (common.Hash{}) || err != nil {
@@ -158,10 +489,74 @@ func (rs *RetryableState) DeleteRetryable(id common.Hash, evm *vm.EVM, scenario
 	_ = retStorage.ClearByUint64(beneficiaryOffset)
 	_ = retStorage.ClearByUint64(timeoutOffset)
 	_ = retStorage.ClearByUint64(timeoutWindowsLeftOffset)
+	if arbosVersion >= 31 {
+		_ = retStorage.ClearByUint64(autoRedeemedOffset)
+		_ = retStorage.ClearByUint64(initialTimeoutOffset)
+		_ = retStorage.ClearByUint64(keepaliveCountOffset)
+		_ = retStorage.ClearByUint64(lastKeepaliveOffset)
+		_ = retStorage.ClearByUint64(pendingRedeemsOffset)
+		_ = retStorage.ClearByUint64(lastRedeemTimeOffset)
+		_ = retStorage.ClearByUint64(windowLifetimeOffset)
+		if err := clearHistory(retStorage.OpenSubStorage(beneficiaryHistoryKey), MaxBeneficiaryHistory); err != nil {
+			return false, err
+		}
+		if err := clearHistory(retStorage.OpenSubStorage(redeemHistoryKey), MaxRedeemHistory); err != nil {
+			return false, err
+		}
+
+		// tickets created before ArbOS 31 weren't counted, so the total saturates at zero
+		calldataSize, err := retStorage.OpenStorageBackedBytes(calldataKey).Size()
+		if err != nil {
+			return false, err
+		}
+		total, err := rs.totalStorageBytes.Get()
+		if err != nil {
+			return false, err
+		}
+		if err := rs.totalStorageBytes.Set(arbmath.SaturatingUSub(total, sizeBytes(calldataSize))); err != nil {
+			return false, err
+		}
+	}
 	err = retStorage.OpenSubStorage(calldataKey).ClearBytes()
 	return true, err
 }
 
+// DeletionGas estimates the storage gas DeleteRetryable will burn for the retryable
+func (retryable *Retryable) DeletionGas(arbosVersion uint64) (uint64, error) {
+	calldataSize, err := retryable.CalldataSize()
+	if err != nil {
+		return 0, err
+	}
+	// timeout, beneficiary, and calldata size
+	reads := uint64(3)
+	// the fixed fields, then the calldata words and its length
+	clears := uint64(timeoutWindowsLeftOffset+1) + arbmath.WordsForBytes(calldataSize) + 1
+	updates := uint64(0)
+	if arbosVersion >= 31 {
+		clears += windowLifetimeOffset - timeoutWindowsLeftOffset
+		reads += 2   // the calldata size and the storage total
+		updates += 1 // the storage total
+		histories := []struct {
+			key        []byte
+			maxEntries uint64
+		}{
+			{beneficiaryHistoryKey, MaxBeneficiaryHistory},
+			{redeemHistoryKey, MaxRedeemHistory},
+		}
+		for _, history := range histories {
+			count, err := retryable.backingStorage.OpenSubStorage(history.key).GetUint64ByUint64(0)
+			if err != nil {
+				return 0, err
+			}
+			reads++
+			if count > 0 {
+				clears += arbmath.MinInt(count, history.maxEntries) + 1
+			}
+		}
+	}
+	return reads*storage.StorageReadCost + clears*storage.StorageWriteZeroCost + updates*storage.StorageWriteCost, nil
+}
+
 func (retryable *Retryable) NumTries() (uint64, error) {
 	return retryable.numTries.Get()
 }
@@ -170,17 +565,177 @@ func (retryable *Retryable) IncrementNumTries() (uint64, error) {
 	return retryable.numTries.Increment()
 }
 
+// AddPendingRedeem records that a redeem was scheduled at the given time and hasn't yet executed
+func (retryable *Retryable) AddPendingRedeem(currentTimestamp uint64) error {
+	if _, err := retryable.pendingRedeems.Increment(); err != nil {
+		return err
+	}
+	return retryable.lastRedeemTime.Set(currentTimestamp)
+}
+
+// FinishPendingRedeem records that one of the ticket's scheduled redeems has begun executing
+func (retryable *Retryable) FinishPendingRedeem() error {
+	pending, err := retryable.pendingRedeems.Get()
+	if err != nil || pending == 0 {
+		// redeems scheduled before ArbOS 31 weren't counted
+		return err
+	}
+	return retryable.pendingRedeems.Set(pending - 1)
+}
+
+// PendingRedeems gets the number of scheduled redeems yet to execute and when the latest was scheduled
+func (retryable *Retryable) PendingRedeems() (uint64, uint64, error) {
+	pending, err := retryable.pendingRedeems.Get()
+	if err != nil || pending == 0 {
+		return 0, 0, err
+	}
+	scheduled, err := retryable.lastRedeemTime.Get()
+	return pending, scheduled, err
+}
+
+// SetBeneficiary changes the beneficiary, recording the previous one in the ticket's bounded history
+func (retryable *Retryable) SetBeneficiary(beneficiary common.Address) error {
+	previous, err := retryable.beneficiary.Get()
+	if err != nil {
+		return err
+	}
+	history := retryable.backingStorage.OpenSubStorage(beneficiaryHistoryKey)
+	count, err := history.GetUint64ByUint64(0)
+	if err != nil {
+		return err
+	}
+	if err := history.SetByUint64(1+count%MaxBeneficiaryHistory, util.AddressToHash(previous)); err != nil {
+		return err
+	}
+	if err := history.SetUint64ByUint64(0, count+1); err != nil {
+		return err
+	}
+	return retryable.beneficiary.Set(beneficiary)
+}
+
+// BeneficiaryHistory gets the most recent prior beneficiaries, oldest first
+func (retryable *Retryable) BeneficiaryHistory() ([]common.Address, error) {
+	history := retryable.backingStorage.OpenSubStorage(beneficiaryHistoryKey)
+	count, err := history.GetUint64ByUint64(0)
+	if err != nil {
+		return nil, err
+	}
+	retained := arbmath.MinInt(count, MaxBeneficiaryHistory)
+	previous := make([]common.Address, 0, retained)
+	for i := count - retained; i < count; i++ {
+		value, err := history.GetByUint64(1 + i%MaxBeneficiaryHistory)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, common.BytesToAddress(value.Bytes()))
+	}
+	return previous, nil
+}
+
+// RecordRedeem appends a scheduled redeem's redeemer and sequence number to the ticket's bounded history
+func (retryable *Retryable) RecordRedeem(redeemer common.Address, sequenceNum uint64) error {
+	history := retryable.backingStorage.OpenSubStorage(redeemHistoryKey)
+	count, err := history.GetUint64ByUint64(0)
+	if err != nil {
+		return err
+	}
+	// the sequence number is packed into the high bytes above the address
+	entry := util.AddressToHash(redeemer)
+	binary.BigEndian.PutUint64(entry[:8], sequenceNum)
+	if err := history.SetByUint64(1+count%MaxRedeemHistory, entry); err != nil {
+		return err
+	}
+	return history.SetUint64ByUint64(0, count+1)
+}
+
+// RedeemHistory gets the redeemers and sequence numbers of the most recent redeems, oldest first
+func (retryable *Retryable) RedeemHistory() ([]common.Address, []uint64, error) {
+	history := retryable.backingStorage.OpenSubStorage(redeemHistoryKey)
+	count, err := history.GetUint64ByUint64(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	retained := arbmath.MinInt(count, MaxRedeemHistory)
+	redeemers := make([]common.Address, 0, retained)
+	sequenceNums := make([]uint64, 0, retained)
+	for i := count - retained; i < count; i++ {
+		entry, err := history.GetByUint64(1 + i%MaxRedeemHistory)
+		if err != nil {
+			return nil, nil, err
+		}
+		redeemers = append(redeemers, common.BytesToAddress(entry.Bytes()))
+		sequenceNums = append(sequenceNums, binary.BigEndian.Uint64(entry[:8]))
+	}
+	return redeemers, sequenceNums, nil
+}
+
+func clearHistory(history *storage.Storage, maxEntries uint64) error {
+	count, err := history.GetUint64ByUint64(0)
+	if err != nil || count == 0 {
+		return err
+	}
+	retained := arbmath.MinInt(count, maxEntries)
+	for i := uint64(1); i <= retained; i++ {
+		if err := history.ClearByUint64(i); err != nil {
+			return err
+		}
+	}
+	return history.ClearByUint64(0)
+}
+
+// WasAutoRedeemed returns whether an auto-redeem was scheduled for the retryable when it was submitted
+func (retryable *Retryable) WasAutoRedeemed() (bool, error) {
+	flag, err := retryable.autoRedeemed.Get()
+	return flag != 0, err
+}
+
+func (retryable *Retryable) SetAutoRedeemed() error {
+	return retryable.autoRedeemed.Set(1)
+}
+
 func (retryable *Retryable) Beneficiary() (common.Address, error) {
 	return retryable.beneficiary.Get()
 }
 
-func (retryable *Retryable) CalculateTimeout() (uint64, error) {
+// CalculateTimeout gets the retryable's expiry, counting each remaining window as the lifetime it was bought with
+func (retryable *Retryable) CalculateTimeout(arbosVersion uint64) (uint64, error) {
 	timeout, err := retryable.timeout.Get()
 	if err != nil {
 		return 0, err
 	}
 	windows, err := retryable.timeoutWindowsLeft.Get()
-	return timeout + windows*RetryableLifetimeSeconds, err
+	if err != nil || windows == 0 {
+		return timeout, err
+	}
+	lifetime, err := windowLifetime(&retryable.windowLifetime, arbosVersion)
+	return arbmath.SaturatingUAdd(timeout, arbmath.SaturatingUMul(windows, lifetime)), err
+}
+
+// Timeout gets the end of the retryable's current window, leaving out any windows still to come
+func (retryable *Retryable) Timeout() (uint64, error) {
+	return retryable.timeout.Get()
+}
+
+// windowLifetime gets the length of each of a ticket's remaining windows, which is fixed when the first is bought.
+// Windows bought before ArbOS 31 weren't recorded, and last the period in effect then.
+func windowLifetime(stored *storage.StorageBackedUint64, arbosVersion uint64) (uint64, error) {
+	if arbosVersion < 31 {
+		return RetryableLifetimeSeconds, nil
+	}
+	lifetime, err := stored.Get()
+	if err != nil || lifetime != 0 {
+		return lifetime, err
+	}
+	return RetryableLifetimeSeconds, nil
+}
+
+// InitialTimeout gets the timeout the retryable was created with, or 0 if it wasn't recorded
+func (retryable *Retryable) InitialTimeout() (uint64, error) {
+	return retryable.initialTimeout.Get()
+}
+
+func (retryable *Retryable) SetInitialTimeout(val uint64) error {
+	return retryable.initialTimeout.Set(val)
 }
 
 func (retryable *Retryable) SetTimeout(val uint64) error {
@@ -191,6 +746,11 @@ func (retryable *Retryable) TimeoutWindowsLeft() (uint64, error) {
 	return retryable.timeoutWindowsLeft.Get()
 }
 
+// KeepaliveCount gets the number of times the retryable's lifetime has been extended
+func (retryable *Retryable) KeepaliveCount() (uint64, error) {
+	return retryable.keepaliveCount.Get()
+}
+
 func (retryable *Retryable) From() (common.Address, error) {
 	return retryable.from.Get()
 }
@@ -203,6 +763,10 @@ func (retryable *Retryable) Callvalue() (*big.Int, error) {
 	return retryable.callvalue.Get()
 }
 
+func (retryable *Retryable) SetCallvalue(val *big.Int) error {
+	return retryable.callvalue.SetChecked(val)
+}
+
 func (retryable *Retryable) Calldata() ([]byte, error) {
 	return retryable.calldata.Get()
 }
@@ -217,6 +781,7 @@ func (rs *RetryableState) Keepalive(
 	currentTimestamp,
 	limitBeforeAdd,
 	timeToAdd uint64,
+	arbosVersion uint64,
 ) (uint64, error) {
 	retryable, err := rs.OpenRetryable(ticketId, currentTimestamp)
 	if err != nil {
@@ -225,13 +790,49 @@ func (rs *RetryableState) Keepalive(
 	if retryable == nil {
 		return 0, errors.New("ticketId not found")
 	}
-	timeout, err := retryable.CalculateTimeout()
+	timeout, err := retryable.CalculateTimeout(arbosVersion)
 	if err != nil {
 		return 0, err
 	}
+	if arbosVersion >= 31 {
+		// every remaining window lasts as long as the first, so a lifetime change only applies once they've run out
+		windows, err := retryable.timeoutWindowsLeft.Get()
+		if err != nil {
+			return 0, err
+		}
+		if windows == 0 {
+			if err := retryable.windowLifetime.Set(timeToAdd); err != nil {
+				return 0, err
+			}
+		} else {
+			timeToAdd, err = windowLifetime(&retryable.windowLifetime, arbosVersion)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
 	if timeout > limitBeforeAdd {
 		return 0, errors.New("timeout too far into the future")
 	}
+	newTimeout, overflow := bits.Add64(timeout, timeToAdd, 0)
+	if overflow != 0 {
+		return 0, errors.New("timeout overflows")
+	}
+	if arbosVersion >= 31 {
+		minInterval, err := rs.minKeepalive.Get()
+		if err != nil {
+			return 0, err
+		}
+		if minInterval != 0 {
+			last, err := retryable.lastKeepalive.Get()
+			if err != nil {
+				return 0, err
+			}
+			if last != 0 && currentTimestamp < arbmath.SaturatingUAdd(last, minInterval) {
+				return 0, fmt.Errorf("keepalive too soon: must wait %v seconds between keepalives", minInterval)
+			}
+		}
+	}
 
 	// Add a duplicate entry to the end of the queue (only the last one deletes the retryable)
 	err = rs.TimeoutQueue.Put(retryable.id)
@@ -241,7 +842,14 @@ func (rs *RetryableState) Keepalive(
 	if _, err := retryable.timeoutWindowsLeft.Increment(); err != nil {
 		return 0, err
 	}
-	newTimeout := timeout + RetryableLifetimeSeconds
+	if arbosVersion >= 31 {
+		if _, err := retryable.keepaliveCount.Increment(); err != nil {
+			return 0, err
+		}
+		if err := retryable.lastKeepalive.Set(currentTimestamp); err != nil {
+			return 0, err
+		}
+	}
 
 	// Pay in advance for the work needed to reap the duplicate from the timeout queue
 	return newTimeout, rs.retryables.Burner().Burn(RetryableReapPrice)
@@ -287,46 +895,122 @@ func (retryable *Retryable) Equals(other *Retryable) (bool, error) { // for test
 	return true, err
 }
 
-func (rs *RetryableState) TryToReapOneRetryable(currentTimestamp uint64, evm *vm.EVM, scenario util.TracingScenario) error {
+// ExpiryBuckets counts the live retryables expiring in each bucketSeconds-long window after currentTimestamp,
+// examining at most maxEntries timeout queue entries. It walks the queue, so it's only meant for off-chain use.
+func (rs *RetryableState) ExpiryBuckets(
+	currentTimestamp, bucketSeconds, numBuckets, maxEntries, arbosVersion uint64,
+) ([]uint64, error) {
+	if bucketSeconds == 0 {
+		return nil, errors.New("bucket size must be nonzero")
+	}
+	buckets := make([]uint64, arbmath.MinInt(numBuckets, MaxExpiryBuckets))
+	seen := make(map[common.Hash]struct{})
+	err := rs.TimeoutQueue.ForEach(func(index uint64, id common.Hash) (bool, error) {
+		if index >= maxEntries {
+			return true, nil
+		}
+		// keepalives add duplicate queue entries, so only count each retryable once
+		if _, ok := seen[id]; ok {
+			return false, nil
+		}
+		seen[id] = struct{}{}
+		retryable, err := rs.OpenRetryable(id, currentTimestamp)
+		if retryable == nil || err != nil {
+			return false, err
+		}
+		timeout, err := retryable.CalculateTimeout(arbosVersion)
+		if err != nil {
+			return false, err
+		}
+		bucket := (timeout - currentTimestamp) / bucketSeconds
+		if bucket < uint64(len(buckets)) {
+			buckets[bucket]++
+		}
+		return false, nil
+	})
+	return buckets, err
+}
+
+func (rs *RetryableState) TryToReapOneRetryable(currentTimestamp uint64, evm *vm.EVM, scenario util.TracingScenario, arbosVersion uint64) error {
+	_, _, err := rs.reapOne(currentTimestamp, evm, scenario, arbosVersion)
+	return err
+}
+
+// SweepExpired reaps the timeout queue until it reaches an unexpired entry or maxToDelete retryables are deleted,
+// returning how many were deleted. Entries that only consume a keepalive window don't count toward the limit.
+func (rs *RetryableState) SweepExpired(
+	maxToDelete int, currentTimestamp uint64, evm *vm.EVM, scenario util.TracingScenario, arbosVersion uint64,
+) (int, error) {
+	deleted := 0
+	for deleted < maxToDelete {
+		reaped, progressed, err := rs.reapOne(currentTimestamp, evm, scenario, arbosVersion)
+		if err != nil || !progressed {
+			return deleted, err
+		}
+		if reaped {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// reapOne processes the head of the timeout queue, reporting whether a retryable was deleted
+// and whether the queue advanced at all
+func (rs *RetryableState) reapOne(
+	currentTimestamp uint64, evm *vm.EVM, scenario util.TracingScenario, arbosVersion uint64,
+) (bool, bool, error) {
 	id, err := rs.TimeoutQueue.Peek()
 	if err != nil || id == nil {
-		return err
+		return false, false, err
 	}
 	retryableStorage := rs.retryables.OpenSubStorage(id.Bytes())
 	timeoutStorage := retryableStorage.OpenStorageBackedUint64(timeoutOffset)
 	timeout, err := timeoutStorage.Get()
 	if err != nil {
-		return err
+		return false, false, err
 	}
 	if timeout == 0 {
 		// The retryable has already been deleted, so discard the peeked entry
 		_, err = rs.TimeoutQueue.Get()
-		return err
+		return false, err == nil, err
 	}
 
 	windowsLeftStorage := retryableStorage.OpenStorageBackedUint64(timeoutWindowsLeftOffset)
 	windowsLeft, err := windowsLeftStorage.Get()
 	if err != nil || timeout >= currentTimestamp {
-		return err
+		return false, false, err
 	}
 
 	// Either the retryable has expired, or it's lost a lifetime's worth of time
 	_, err = rs.TimeoutQueue.Get()
 	if err != nil {
-		return err
+		return false, false, err
 	}
 
 	if windowsLeft == 0 {
 		// the retryable has expired, time to reap
-		_, err = rs.DeleteRetryable(*id, evm, scenario)
-		return err
+		if _, err := rs.DeleteRetryable(*id, evm, scenario, arbosVersion); err != nil {
+			return false, false, err
+		}
+		if arbosVersion >= 31 {
+			if err := rs.IncrementRetryablesExpired(); err != nil {
+				return false, false, err
+			}
+		}
+		return true, true, nil
 	}
 
-	// Consume a window, delaying the timeout one lifetime period
-	if err := timeoutStorage.Set(timeout + RetryableLifetimeSeconds); err != nil {
-		return err
+	// Consume a window, delaying the timeout by the lifetime it was bought with
+	lifetimeStorage := retryableStorage.OpenStorageBackedUint64(windowLifetimeOffset)
+	lifetime, err := windowLifetime(&lifetimeStorage, arbosVersion)
+	if err != nil {
+		return false, false, err
 	}
-	return windowsLeftStorage.Set(windowsLeft - 1)
+	if err := timeoutStorage.Set(timeout + lifetime); err != nil {
+		return false, false, err
+	}
+	err = windowsLeftStorage.Set(windowsLeft - 1)
+	return false, err == nil, err
 }
 
 func (retryable *Retryable) MakeTx(chainId *big.Int, nonce uint64, gasFeeCap *big.Int, gas uint64, ticketId common.Hash, refundTo common.Address, maxRefund *big.Int, submissionFeeRefund *big.Int) (*types.ArbitrumRetryTx, error) {
@@ -369,3 +1053,26 @@ func RetryableEscrowAddress(ticketId common.Hash) common.Address {
 func RetryableSubmissionFee(calldataLengthInBytes int, l1BaseFee *big.Int) *big.Int {
 	return arbmath.BigMulByUint(l1BaseFee, uint64(1400+6*calldataLengthInBytes))
 }
+
+// ValidateSubmissionAmounts rejects submissions whose amounts no uint256 balance could represent
+func ValidateSubmissionAmounts(deposit, callvalue, maxSubmissionFee, gasFeeCap *big.Int) error {
+	amounts := []struct {
+		name  string
+		value *big.Int
+	}{
+		{"deposit", deposit},
+		{"callvalue", callvalue},
+		{"max submission fee", maxSubmissionFee},
+		{"gas fee cap", gasFeeCap},
+	}
+	for _, amount := range amounts {
+		if amount.value == nil || amount.value.Sign() < 0 || amount.value.BitLen() > 256 {
+			return fmt.Errorf("invalid retryable %v %v", amount.name, amount.value)
+		}
+	}
+	// the callvalue and submission fee must both be paid from the sender's balance
+	if total := arbmath.BigAdd(callvalue, maxSubmissionFee); total.BitLen() > 256 {
+		return fmt.Errorf("retryable callvalue %v plus max submission fee %v exceeds any possible balance", callvalue, maxSubmissionFee)
+	}
+	return nil
+}
@@ -199,6 +199,14 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 		from := tx.From
 		scenario := util.TracingDuringEVM
 
+		// minting or moving an amount no balance could hold would panic, so reject those up front
+		if p.state.ArbOSVersion() >= 31 {
+			err := retryables.ValidateSubmissionAmounts(tx.DepositValue, tx.RetryValue, tx.MaxSubmissionFee, tx.GasFeeCap)
+			if err != nil {
+				return true, 0, err, nil
+			}
+		}
+
 		// mint funds with the deposit, then charge fees later
 		availableRefund := new(big.Int).Set(tx.DepositValue)
 		takeFunds(availableRefund, tx.RetryValue)
@@ -263,7 +271,9 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 		}
 
 		time := evm.Context.Time
-		timeout := time + retryables.RetryableLifetimeSeconds
+		lifetime, err := p.state.RetryableState().Lifetime(p.state.ArbOSVersion())
+		p.state.Restrict(err)
+		timeout := arbmath.SaturatingUAdd(time, lifetime)
 
 		// we charge for creating the retryable and reaping the next expired one on L1
 		retryable, err := p.state.RetryableState().CreateRetryable(
@@ -276,6 +286,12 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 			tx.RetryData,
 		)
 		p.state.Restrict(err)
+		if p.state.ArbOSVersion() >= 31 {
+			p.state.Restrict(retryable.SetInitialTimeout(timeout))
+			p.state.Restrict(p.state.RetryableState().IncrementSubmissionCount(tx.From))
+			p.state.Restrict(p.state.RetryableState().IncrementRetryablesCreated())
+			p.state.Restrict(p.state.RetryableState().TrackStorage(retryable))
+		}
 
 		err = EmitTicketCreatedEvent(evm, ticketId)
 		if err != nil {
@@ -292,11 +308,18 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 			effectiveBaseFee = common.Big0
 		}
 
+		manualRedeemOnly := false
+		if p.state.ArbOSVersion() >= 31 && tx.RetryTo != nil {
+			manualRedeemOnly, err = p.state.RetryableState().ManualRedeemOnly().IsMember(*tx.RetryTo)
+			p.state.Restrict(err)
+		}
+
 		maxGasCost := arbmath.BigMulByUint(tx.GasFeeCap, usergas)
 		maxFeePerGasTooLow := arbmath.BigLessThan(tx.GasFeeCap, effectiveBaseFee)
-		if arbmath.BigLessThan(balance.ToBig(), maxGasCost) || usergas < params.TxGas || maxFeePerGasTooLow {
+		if arbmath.BigLessThan(balance.ToBig(), maxGasCost) || usergas < params.TxGas || maxFeePerGasTooLow || manualRedeemOnly {
 			// User either specified too low of a gas fee cap, didn't have enough balance to pay for gas,
-			// or the specified gas limit is below the minimum transaction gas cost.
+			// the specified gas limit is below the minimum transaction gas cost,
+			// or the destination only accepts manual redeems.
 			// Either way, attempt to refund the gas costs, since we're not doing the auto-redeem.
 			gasCostRefund := takeFunds(availableRefund, maxGasCost)
 			if err := transfer(&tx.From, &tx.FeeRefundAddr, gasCostRefund); err != nil {
@@ -360,6 +383,9 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 
 		_, err = retryable.IncrementNumTries()
 		p.state.Restrict(err)
+		if p.state.ArbOSVersion() >= 31 {
+			p.state.Restrict(retryable.SetAutoRedeemed())
+		}
 
 		err = EmitReedeemScheduledEvent(
 			evm,
@@ -394,6 +420,15 @@ func (p *TxProcessor) StartTxHook() (endTxNow bool, gasUsed uint64, err error, r
 			return true, 0, err, nil
 		}
 
+		if p.state.ArbOSVersion() >= 31 {
+			// this redeem is no longer pending, so it no longer holds off cancellation
+			retryable, err := p.state.RetryableState().OpenRetryable(tx.TicketId, evm.Context.Time)
+			p.state.Restrict(err)
+			if retryable != nil {
+				p.state.Restrict(retryable.FinishPendingRedeem())
+			}
+		}
+
 		// The redeemer has pre-paid for this tx's gas
 		prepaid := arbmath.BigMulByUint(evm.Context.BaseFee, tx.Gas)
 		util.MintBalance(&tx.From, prepaid, evm, scenario, "prepaid")
@@ -578,7 +613,7 @@ func (p *TxProcessor) EndTxHook(gasLeft uint64, success bool) {
 			// we don't want to charge for this
 			tracingInfo := util.NewTracingInfo(p.evm, arbosAddress, p.msg.From, scenario)
 			state := arbosState.OpenSystemArbosStateOrPanic(p.evm.StateDB, tracingInfo, false)
-			_, _ = state.RetryableState().DeleteRetryable(inner.TicketId, p.evm, scenario)
+			_, _ = state.RetryableState().DeleteRetryable(inner.TicketId, p.evm, scenario, state.ArbOSVersion())
 		} else {
 			// return the Callvalue to escrow
 			escrow := retryables.RetryableEscrowAddress(inner.TicketId)
@@ -589,6 +624,12 @@ func (p *TxProcessor) EndTxHook(gasLeft uint64, success bool) {
 				panic(err)
 			}
 		}
+		if p.state.ArbOSVersion() >= 31 {
+			if err := EmitRedeemedEvent(p.evm, inner.TicketId, gasUsed, success); err != nil {
+				log.Error("failed to emit Redeemed event", "err", err)
+			}
+		}
+
 		// we've already credited the network fee account, but we didn't charge the gas pool yet
 		p.state.Restrict(p.state.L2PricingState().AddToGasPool(-arbmath.SaturatingCast[int64](gasUsed)))
 		return
@@ -0,0 +1,429 @@
+// Copyright 2021-2022, Offchain Labs, Inc.
+// For license information, see https://github.com/OffchainLabs/nitro-contracts/blob/main/LICENSE
+// SPDX-License-Identifier: BUSL-1.1
+
+pragma solidity >=0.4.21 <0.9.0;
+
+/**
+ * @title Methods for managing retryables.
+ * @notice Precompiled contract in every Arbitrum chain for retryable transaction related data retrieval and interactions. Exists at 0x000000000000000000000000000000000000006e
+ */
+interface ArbRetryableTx {
+    /**
+     * @notice Schedule an attempt to redeem a redeemable tx, donating all of the call's gas to the redeem.
+     * Revert if ticketId does not exist.
+     * @param ticketId unique identifier of retryable message: keccak256(keccak256(ArbchainId, inbox-sequence-number), uint(0) )
+     * @return txId that the redeem attempt will have
+     */
+    function redeem(
+        bytes32 ticketId
+    ) external returns (bytes32);
+
+    /**
+     * @notice Schedule a redeem like redeem, reverting if the retry would be priced above maxGasPrice
+     * Available in ArbOS version 31 and above
+     */
+    function redeemAtGasPrice(
+        bytes32 ticketId,
+        uint256 maxGasPrice
+    ) external returns (bytes32);
+
+    /**
+     * @notice Schedule a redeem like redeem, donating at most gasLimit and leaving the rest with the caller
+     * Available in ArbOS version 31 and above
+     */
+    function redeemWithGasLimit(
+        bytes32 ticketId,
+        uint64 gasLimit
+    ) external returns (bytes32);
+
+    /**
+     * @notice Schedule a redeem like redeem, crediting the retry's gas refund to redeemer instead of the caller.
+     * Only the ticket's beneficiary or the batch poster may redeem on another's behalf.
+     * Available in ArbOS version 31 and above
+     */
+    function redeemTo(
+        bytes32 ticketId,
+        address redeemer
+    ) external returns (bytes32);
+
+    /**
+     * @notice Preview a redeem by the caller without scheduling it
+     * Available in ArbOS version 31 and above
+     * @return txId the retry would have
+     * @return donatedGas the redeem would donate
+     */
+    function simulateRedeem(
+        bytes32 ticketId
+    ) external view returns (bytes32 txId, uint64 donatedGas);
+
+    /**
+     * @notice Estimate the gas processTickets burns to schedule redeems of the tickets, excluding donated gas
+     * Available in ArbOS version 31 and above
+     */
+    function estimateBatchRedeemOverhead(
+        bytes32[] calldata ticketIds
+    ) external view returns (uint64);
+
+    /**
+     * @notice Return the number of redeem attempts a ticket may have, or 0 if unlimited
+     * Available in ArbOS version 31 and above
+     */
+    function getMaxTries() external view returns (uint64);
+
+    /**
+     * @notice Return whether exhausted tickets reject redeems (0) or are cancelled (1)
+     * Available in ArbOS version 31 and above
+     */
+    function getMaxTriesPolicy() external view returns (uint64);
+
+    /**
+     * @notice Extend the lifetimes of keepaliveIds, then schedule redeems of redeemIds,
+     * splitting the remaining gas evenly among them
+     * Available in ArbOS version 31 and above
+     * @return txIds of the scheduled redeems
+     * @return newTimeouts of the kept-alive tickets
+     */
+    function processTickets(
+        bytes32[] calldata redeemIds,
+        bytes32[] calldata keepaliveIds
+    ) external returns (bytes32[] memory txIds, uint256[] memory newTimeouts);
+
+    /**
+     * @notice Return the minimum lifetime of redeemable txn.
+     * @return lifetime in seconds
+     */
+    function getLifetime() external view returns (uint256);
+
+    /**
+     * @notice Return the base submission fee and the fee per byte of calldata
+     * Available in ArbOS version 31 and above
+     */
+    function getSubmissionPrice(
+        uint64 dataSize
+    ) external view returns (uint256 base, uint256 perByte);
+
+    /**
+     * @notice Return the cost of keeping a retryable alive, in wei per byte per second
+     * Available in ArbOS version 31 and above
+     */
+    function getRentRateWei() external view returns (uint256);
+
+    /**
+     * @notice Return the rounding policy applied to rent charges
+     * Available in ArbOS version 31 and above
+     */
+    function getRoundingPolicy() external view returns (uint64);
+
+    /**
+     * @notice Return the gas price a retry scheduled now would run at
+     * Available in ArbOS version 31 and above
+     */
+    function getRetryGasPrice() external view returns (uint256);
+
+    /**
+     * @notice Return the most gas redeem will donate to a retry, or 0 if uncapped
+     * Available in ArbOS version 31 and above
+     */
+    function getMaxRedeemGas() external view returns (uint64);
+
+    /**
+     * @notice Return the timestamp when ticketId will age out, reverting if it does not exist
+     * @param ticketId unique ticket identifier
+     * @return timestamp for ticket's deadline
+     */
+    function getTimeout(
+        bytes32 ticketId
+    ) external view returns (uint256);
+
+    /**
+     * @notice Return the timestamp each ticket will expire at, using 0 for missing or expired tickets
+     * Available in ArbOS version 31 and above
+     */
+    function getTimeouts(
+        bytes32[] calldata ticketIds
+    ) external view returns (uint256[] memory);
+
+    /**
+     * @notice Return the number of redeem attempts scheduled for the ticket
+     * Available in ArbOS version 31 and above
+     */
+    function getNumTries(
+        bytes32 ticketId
+    ) external view returns (uint64);
+
+    /**
+     * @notice Return the seconds until the ticket expires, or 0 if it already has but hasn't been reaped
+     * Available in ArbOS version 31 and above
+     */
+    function getTimeRemaining(
+        bytes32 ticketId
+    ) external view returns (uint256);
+
+    /**
+     * @notice Return the timestamp the ticket was set to expire at when it was created
+     * Available in ArbOS version 31 and above
+     */
+    function getInitialTimeout(
+        bytes32 ticketId
+    ) external view returns (uint256);
+
+    /**
+     * @notice Adds one lifetime period to the life of ticketId.
+     * Donate gas to pay for the lifetime extension.
+     * If successful, emits LifetimeExtended event.
+     * Revert if ticketId does not exist, or if the timeout of ticketId is already at least one lifetime period in the future.
+     * @param ticketId unique ticket identifier
+     * @return new timeout of ticketId
+     */
+    function keepalive(
+        bytes32 ticketId
+    ) external returns (uint256);
+
+    /**
+     * @notice Quote the gas keepalive would charge for the ticket now
+     * Available in ArbOS version 31 and above
+     */
+    function getKeepaliveCost(
+        bytes32 ticketId
+    ) external view returns (uint256);
+
+    /**
+     * @notice Extend the lifetime of each ticket, reverting if any is missing
+     * Available in ArbOS version 31 and above
+     */
+    function keepaliveBatch(
+        bytes32[] calldata ticketIds
+    ) external returns (uint256[] memory);
+
+    /**
+     * @notice Return the cumulative wei paid to extend retryable lifetimes
+     * Available in ArbOS version 31 and above
+     */
+    function getTotalRentCollected() external view returns (uint256);
+
+    /**
+     * @notice Return the number of times the ticket's lifetime has been extended
+     * Available in ArbOS version 31 and above
+     */
+    function getKeepaliveCount(
+        bytes32 ticketId
+    ) external view returns (uint64);
+
+    /**
+     * @notice Return the beneficiary of ticketId.
+     * Revert if ticketId doesn't exist.
+     * @param ticketId unique ticket identifier
+     * @return address of beneficiary for ticket
+     */
+    function getBeneficiary(
+        bytes32 ticketId
+    ) external view returns (address);
+
+    /**
+     * @notice Return the beneficiary of each ticket, using the zero address for missing or expired tickets
+     * Available in ArbOS version 31 and above
+     */
+    function getBeneficiaries(
+        bytes32[] calldata ticketIds
+    ) external view returns (address[] memory);
+
+    /**
+     * @notice Return the ticket's sender, destination, callvalue, beneficiary, and calldata
+     * Available in ArbOS version 31 and above
+     */
+    function getRetryableData(
+        bytes32 ticketId
+    )
+        external
+        view
+        returns (
+            address from,
+            address to,
+            uint256 callvalue,
+            address beneficiary,
+            bytes memory data
+        );
+
+    /**
+     * @notice Return the bytes of state held by live retryables created since ArbOS 31
+     * Available in ArbOS version 31 and above
+     */
+    function getTotalRetryableBytes() external view returns (uint64);
+
+    /**
+     * @notice Return the callvalue held in escrow for the ticket
+     * Available in ArbOS version 31 and above
+     */
+    function getEscrowedValue(
+        bytes32 ticketId
+    ) external view returns (uint256);
+
+    /**
+     * @notice Return the number of bytes of state the ticket occupies
+     * Available in ArbOS version 31 and above
+     */
+    function getRetryableSizeBytes(
+        bytes32 ticketId
+    ) external view returns (uint64);
+
+    /**
+     * @notice Return the ticket's most recent prior beneficiaries, oldest first
+     * Available in ArbOS version 31 and above
+     */
+    function getBeneficiaryHistory(
+        bytes32 ticketId
+    ) external view returns (address[] memory);
+
+    /**
+     * @notice Return the redeemer and sequence number of each of the ticket's most recent redeem attempts
+     * Available in ArbOS version 31 and above
+     */
+    function getRedeemHistory(
+        bytes32 ticketId
+    ) external view returns (address[] memory redeemers, uint64[] memory sequenceNums);
+
+    /**
+     * @notice Return whether an auto-redeem was attempted when the ticket was submitted
+     * Available in ArbOS version 31 and above
+     */
+    function wasAutoRedeemed(
+        bytes32 ticketId
+    ) external view returns (bool);
+
+    /**
+     * @notice Return whether retryables to the destination skip the auto-redeem at submission
+     * Available in ArbOS version 31 and above
+     */
+    function isManualRedeemOnly(
+        address destination
+    ) external view returns (bool);
+
+    /**
+     * @notice Estimate the gas cancel will consume for the ticket
+     * Available in ArbOS version 31 and above
+     */
+    function getCancelGasEstimate(
+        bytes32 ticketId
+    ) external view returns (uint64);
+
+    /**
+     * @notice Cancel ticketId and refund its callvalue to its beneficiary.
+     * Revert if ticketId doesn't exist, or if called by anyone other than ticketId's beneficiary.
+     * @param ticketId unique ticket identifier
+     */
+    function cancel(
+        bytes32 ticketId
+    ) external;
+
+    /**
+     * @notice Cancel each ticket like cancel, reverting the whole batch if the caller can't cancel any of them
+     * Available in ArbOS version 31 and above
+     */
+    function cancelBatch(
+        bytes32[] calldata ticketIds
+    ) external;
+
+    /**
+     * @notice Hand a ticket to a new beneficiary (caller must be the current beneficiary)
+     * Available in ArbOS version 31 and above
+     */
+    function transferBeneficiary(
+        bytes32 ticketId,
+        address newBeneficiary
+    ) external;
+
+    /**
+     * @notice Cancel a ticket, moving its escrowed callvalue into another ticket
+     * (caller must be the beneficiary of both)
+     * Available in ArbOS version 31 and above
+     */
+    function cancelAndFund(
+        bytes32 cancelTicketId,
+        bytes32 fundTicketId
+    ) external;
+
+    /**
+     * @notice Delete a ticket that has fully expired but hasn't been reaped, refunding its escrow to the beneficiary
+     * Available in ArbOS version 31 and above
+     * @return false if the ticket is already gone
+     */
+    function tryDelete(
+        bytes32 ticketId
+    ) external returns (bool);
+
+    /**
+     * @notice Reap expired retryables from the front of the timeout queue, deleting at most maxToDelete of them
+     * Available in ArbOS version 31 and above
+     * @return the number of retryables deleted
+     */
+    function sweepExpired(
+        uint64 maxToDelete
+    ) external returns (uint64);
+
+    /**
+     * @notice Return the number of retryables submitted by the sender, which is aliased for L1 contracts
+     * Available in ArbOS version 31 and above
+     */
+    function getSubmissionNonce(
+        address l1Sender
+    ) external view returns (uint64);
+
+    /**
+     * @notice Gets the redeemer of the current retryable redeem attempt.
+     * Returns the zero address if the current transaction is not a retryable redeem attempt.
+     * If this is an auto-redeem, returns the fee refund address of the retryable.
+     */
+    function getCurrentRedeemer() external view returns (address);
+
+    /**
+     * @notice Return the id of the retryable being redeemed, or zero outside of a retry
+     * Available in ArbOS version 31 and above
+     */
+    function getCurrentTicketId() external view returns (bytes32);
+
+    /**
+     * @notice Do not call. This method represents a retryable submission to aid explorers.
+     * Calling it will always revert.
+     */
+    function submitRetryable(
+        bytes32 requestId,
+        uint256 l1BaseFee,
+        uint256 deposit,
+        uint256 callvalue,
+        uint256 gasFeeCap,
+        uint64 gasLimit,
+        uint256 maxSubmissionFee,
+        address feeRefundAddress,
+        address beneficiary,
+        address retryTo,
+        bytes calldata retryData
+    ) external;
+
+    event TicketCreated(bytes32 indexed ticketId);
+    event LifetimeExtended(bytes32 indexed ticketId, uint256 newTimeout);
+    event RedeemScheduled(
+        bytes32 indexed ticketId,
+        bytes32 indexed retryTxHash,
+        uint64 indexed sequenceNum,
+        uint64 donatedGas,
+        address gasDonor,
+        uint256 maxRefund,
+        uint256 submissionFeeRefund
+    );
+    event Canceled(bytes32 indexed ticketId);
+
+    /// @notice emitted after each retry attempt with the gas it used and whether it succeeded
+    event Redeemed(bytes32 indexed ticketId, uint64 gasUsed, bool success);
+
+    event BeneficiaryTransferred(
+        bytes32 indexed ticketId,
+        address indexed oldBeneficiary,
+        address indexed newBeneficiary
+    );
+
+    error NoTicketWithID();
+    error NotCallable();
+    error TicketExpired();
+    error NotBeneficiary(address caller);
+}
@@ -19,7 +19,6 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/offchainlabs/nitro/arbos/arbosState"
-	"github.com/offchainlabs/nitro/arbos/retryables"
 	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
@@ -244,15 +243,11 @@ func (api *ArbDebugAPI) TimeoutQueue(ctx context.Context, blockNum rpc.BlockNumb
 			queue.Timeouts = append(queue.Timeouts, 0)
 			return false, nil
 		}
-		timeout, err := retryable.CalculateTimeout()
+		// the queue entry's own timeout is the current window's, so leave out the remaining windows
+		timeout, err := retryable.Timeout()
 		if err != nil {
 			return false, err
 		}
-		windows, err := retryable.TimeoutWindowsLeft()
-		if err != nil {
-			return false, err
-		}
-		timeout -= windows * retryables.RetryableLifetimeSeconds
 
 		queue.Tickets = append(queue.Tickets, ticket)
 		queue.Timeouts = append(queue.Timeouts, timeout)
@@ -263,6 +258,36 @@ func (api *ArbDebugAPI) TimeoutQueue(ctx context.Context, blockNum rpc.BlockNumb
 	return queue, err
 }
 
+type ExpiryBuckets struct {
+	BlockNumber   uint64   `json:"blockNumber"`
+	BucketSeconds uint64   `json:"bucketSeconds"`
+	Counts        []uint64 `json:"counts"`
+}
+
+// ExpiryBuckets counts the live retryables expiring in each bucketSeconds-long window after the block,
+// examining no more of the timeout queue than the configured bound
+func (api *ArbDebugAPI) ExpiryBuckets(
+	ctx context.Context, blockNum rpc.BlockNumber, bucketSeconds, numBuckets uint64,
+) (ExpiryBuckets, error) {
+
+	blockNum, _ = api.blockchain.ClipToPostNitroGenesis(blockNum)
+
+	buckets := ExpiryBuckets{
+		BlockNumber:   uint64(blockNum),
+		BucketSeconds: bucketSeconds,
+		Counts:        []uint64{},
+	}
+
+	state, header, err := stateAndHeader(api.blockchain, uint64(blockNum))
+	if err != nil {
+		return buckets, err
+	}
+	buckets.Counts, err = state.RetryableState().ExpiryBuckets(
+		header.Time, bucketSeconds, numBuckets, api.timeoutQueueBound, state.ArbOSVersion(),
+	)
+	return buckets, err
+}
+
 func stateAndHeader(blockchain *core.BlockChain, block uint64) (*arbosState.ArbosState, *types.Header, error) {
 	header := blockchain.GetHeaderByNumber(block)
 	if !blockchain.Config().IsArbitrumNitro(header.Number) {
@@ -37,7 +37,7 @@ func (n NodeInterfaceDebug) GetRetryable(c ctx, evm mech, ticket bytes32) (Retry
 		return RetryableInfo{}, fmt.Errorf("no retryable with id %v exists", ticket)
 	}
 
-	timeout, _ := retryable.CalculateTimeout()
+	timeout, _ := retryable.CalculateTimeout(c.State.ArbOSVersion())
 	from, _ := retryable.From()
 	toPointer, _ := retryable.To()
 	callvalue, _ := retryable.Callvalue()
@@ -10,11 +10,15 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/offchainlabs/nitro/arbos"
 	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbos/burn"
 	"github.com/offchainlabs/nitro/util/testhelpers"
 )
 
@@ -176,6 +180,21 @@ func newMockEVMForTestingWithVersion(version *uint64) *vm.EVM {
 	return evm
 }
 
+// newMockEVMForTestingAtArbOSVersion is like newMockEVMForTestingWithVersion, but its ArbOS state starts at version too
+func newMockEVMForTestingAtArbOSVersion(version uint64) *vm.EVM {
+	evm := newMockEVMForTestingWithVersion(&version)
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		panic(err)
+	}
+	burner := burn.NewSystemBurner(nil, false)
+	if _, err := arbosState.InitializeArbosState(statedb, burner, evm.ChainConfig(), arbostypes.TestInitMessage); err != nil {
+		panic(err)
+	}
+	evm.StateDB = statedb
+	return evm
+}
+
 func Require(t *testing.T, err error, printables ...interface{}) {
 	t.Helper()
 	testhelpers.RequireImpl(t, err, printables...)
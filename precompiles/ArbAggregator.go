@@ -15,6 +15,11 @@ import (
 // is invoked to change it.
 type ArbAggregator struct {
 	Address addr // 0x6d
+
+	FeeCollectorUpdated        func(ctx, mech, addr, addr, addr) error
+	FeeCollectorUpdatedGasCost func(addr, addr, addr) (uint64, error)
+
+	NotAuthorizedFeeCollectorError func(addr, addr) error
 }
 
 var ErrNotOwner = errors.New("must be called by chain owner")
@@ -67,6 +72,49 @@ func (con ArbAggregator) GetFeeCollector(c ctx, evm mech, batchPoster addr) (add
 	return posterInfo.PayTo()
 }
 
+// GetFeeCollectors gets each batch poster's fee collector, in order
+func (con ArbAggregator) GetFeeCollectors(c ctx, evm mech, batchPosters []addr) ([]addr, error) {
+	collectors := make([]addr, 0, len(batchPosters))
+	for _, batchPoster := range batchPosters {
+		collector, err := con.GetFeeCollector(c, evm, batchPoster)
+		if err != nil {
+			return nil, err
+		}
+		collectors = append(collectors, collector)
+	}
+	return collectors, nil
+}
+
+// GetAggregatorsForCollector gets the batch posters whose fees are paid to the collector
+func (con ArbAggregator) GetAggregatorsForCollector(c ctx, evm mech, collector addr) ([]addr, error) {
+	return c.State.L1PricingState().BatchPosterTable().PostersPayingTo(collector)
+}
+
+// GetCollectedFees gets the reimbursement a batch poster is owed but hasn't yet been paid.
+// ArbOS pays it to the poster's fee collector as L1 fees become available, so there's nothing to withdraw.
+func (con ArbAggregator) GetCollectedFees(c ctx, evm mech, batchPoster addr) (huge, error) {
+	posterInfo, err := c.State.L1PricingState().BatchPosterTable().OpenPoster(batchPoster, false)
+	if err != nil {
+		return nil, err
+	}
+	return posterInfo.FundsDue()
+}
+
+// GetAggregatorConfig gets a batch poster's fee collector, tx base fee, compression ratio, and whether it's the default.
+// The tx base fee and compression ratio are deprecated and always zero.
+func (con ArbAggregator) GetAggregatorConfig(c ctx, evm mech, aggregator addr) (addr, huge, uint64, bool, error) {
+	feeCollector, err := con.GetFeeCollector(c, evm, aggregator)
+	if err != nil {
+		return addr{}, nil, 0, false, err
+	}
+	return feeCollector, big.NewInt(0), 0, aggregator == l1pricing.BatchPosterAddress, nil
+}
+
+// IsFeeCollector checks whether the account is the fee collector of any batch poster
+func (con ArbAggregator) IsFeeCollector(c ctx, evm mech, account addr) (bool, error) {
+	return c.State.L1PricingState().BatchPosterTable().HasPayTo(account)
+}
+
 // SetFeeCollector sets a batch poster's fee collector (caller must be the batch poster, its fee collector, or an owner)
 func (con ArbAggregator) SetFeeCollector(c ctx, evm mech, batchPoster addr, newFeeCollector addr) error {
 	posterInfo, err := c.State.L1PricingState().BatchPosterTable().OpenPoster(batchPoster, false)
@@ -83,10 +131,19 @@ func (con ArbAggregator) SetFeeCollector(c ctx, evm mech, batchPoster addr, newF
 			return err
 		}
 		if !isOwner {
+			if c.State.ArbOSVersion() >= 31 {
+				return con.NotAuthorizedFeeCollectorError(c.caller, batchPoster)
+			}
 			return errors.New("only a batch poster (or its fee collector / chain owner) may change its fee collector")
 		}
 	}
-	return posterInfo.SetPayTo(newFeeCollector)
+	if err := posterInfo.SetPayTo(newFeeCollector, c.State.ArbOSVersion()); err != nil {
+		return err
+	}
+	if c.State.ArbOSVersion() >= 31 {
+		return con.FeeCollectorUpdated(c, evm, batchPoster, oldFeeCollector, newFeeCollector)
+	}
+	return nil
 }
 
 // GetTxBaseFee gets an aggregator's current fixed fee to submit a tx
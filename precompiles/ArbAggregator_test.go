@@ -4,12 +4,17 @@
 package precompiles
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/offchainlabs/nitro/arbos/l1pricing"
+	"github.com/offchainlabs/nitro/arbos/storage"
+	templates "github.com/offchainlabs/nitro/solgen/go/precompilesgen"
 )
 
 func TestArbAggregatorBatchPosters(t *testing.T) {
@@ -40,16 +45,58 @@ func TestArbAggregatorBatchPosters(t *testing.T) {
 	}
 }
 
+func TestArbAggregatorBatchPostersGasScales(t *testing.T) {
+	evm := newMockEVMForTesting()
+	context := testContext(common.Address{}, evm)
+
+	aggregatorABI, err := templates.ArbAggregatorMetaData.GetAbi()
+	Require(t, err)
+	calldata, err := aggregatorABI.Pack("getBatchPosters")
+	Require(t, err)
+
+	gasUsed := func() uint64 {
+		t.Helper()
+		gasSupplied := uint64(1000000)
+		_, gasLeft, err := Precompiles()[types.ArbAggregatorAddress].Call(
+			calldata,
+			types.ArbAggregatorAddress,
+			types.ArbAggregatorAddress,
+			common.Address{},
+			big.NewInt(0),
+			true,
+			gasSupplied,
+			evm,
+		)
+		Require(t, err)
+		return gasSupplied - gasLeft
+	}
+
+	before := gasUsed()
+	Require(t, ArbDebug{}.BecomeChainOwner(context, evm))
+	Require(t, ArbAggregator{}.AddBatchPoster(context, evm, common.BytesToAddress(crypto.Keccak256([]byte{})[:20])))
+	after := gasUsed()
+
+	// each additional batch poster costs at least one more storage read
+	if after < before+storage.StorageReadCost {
+		Fail(t, "enumerating batch posters didn't charge per entry", before, after)
+	}
+}
+
 func TestFeeCollector(t *testing.T) {
 	evm := newMockEVMForTesting()
-	agg := ArbAggregator{}
+	//nolint:errcheck
+	agg := Precompiles()[types.ArbAggregatorAddress].Precompile().implementer.Interface().(*ArbAggregator)
 
 	aggAddr := l1pricing.BatchPosterAddress
 	collectorAddr := common.BytesToAddress(crypto.Keccak256([]byte{1})[:20])
 	impostorAddr := common.BytesToAddress(crypto.Keccak256([]byte{2})[:20])
 
-	aggCtx := testContext(aggAddr, evm)
 	callerCtx := testContext(common.Address{}, evm)
+	if callerCtx.State.ArbOSVersion() < 31 {
+		Require(t, callerCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	// opened after the upgrade so they see ArbOS 31
+	aggCtx := testContext(aggAddr, evm)
 	collectorCtx := testContext(collectorAddr, evm)
 	imposterCtx := testContext(impostorAddr, evm)
 
@@ -78,6 +125,18 @@ func TestFeeCollector(t *testing.T) {
 
 	// but the fee collector can replace itself
 	Require(t, agg.SetFeeCollector(collectorCtx, evm, aggAddr, impostorAddr))
+
+	// only the current fee collector should be reported as one
+	isCollector, err := agg.IsFeeCollector(callerCtx, evm, impostorAddr)
+	Require(t, err)
+	if !isCollector {
+		Fail(t)
+	}
+	isCollector, err = agg.IsFeeCollector(callerCtx, evm, collectorAddr)
+	Require(t, err)
+	if isCollector {
+		Fail(t)
+	}
 }
 
 func TestTxBaseFee(t *testing.T) {
@@ -109,3 +168,113 @@ func TestTxBaseFee(t *testing.T) {
 		Fail(t, fee)
 	}
 }
+
+func TestFeeCollectorUpdatedEvent(t *testing.T) {
+	evm := newMockEVMForTesting()
+	//nolint:errcheck
+	agg := Precompiles()[types.ArbAggregatorAddress].Precompile().implementer.Interface().(*ArbAggregator)
+
+	aggAddr := l1pricing.BatchPosterAddress
+	collectorAddr := common.BytesToAddress(crypto.Keccak256([]byte{1})[:20])
+	aggCtx := testContext(aggAddr, evm)
+	if aggCtx.State.ArbOSVersion() < 31 {
+		Require(t, aggCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	Require(t, agg.SetFeeCollector(aggCtx, evm, aggAddr, collectorAddr))
+
+	aggContract, err := templates.NewArbAggregator(common.Address{}, nil)
+	Require(t, err)
+	//nolint:errcheck
+	logs := evm.StateDB.(*state.StateDB).Logs()
+	if len(logs) != 1 {
+		Fail(t, "expected one log", len(logs))
+	}
+	updated, err := aggContract.ParseFeeCollectorUpdated(*logs[0])
+	Require(t, err)
+	if updated.BatchPoster != aggAddr || updated.OldFeeCollector != aggAddr || updated.NewFeeCollector != collectorAddr {
+		Fail(t, "wrong fee collector update", updated.BatchPoster, updated.OldFeeCollector, updated.NewFeeCollector)
+	}
+}
+
+func TestFeeCollectorRevertData(t *testing.T) {
+	evm := newMockEVMForTesting()
+	aggCtx := testContext(l1pricing.BatchPosterAddress, evm)
+	if aggCtx.State.ArbOSVersion() < 31 {
+		Require(t, aggCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	aggregatorABI, err := templates.ArbAggregatorMetaData.GetAbi()
+	Require(t, err)
+	impostor := common.BytesToAddress(crypto.Keccak256([]byte{2})[:20])
+	calldata, err := aggregatorABI.Pack("setFeeCollector", l1pricing.BatchPosterAddress, impostor)
+	Require(t, err)
+	output, _, err := Precompiles()[types.ArbAggregatorAddress].Call(
+		calldata,
+		types.ArbAggregatorAddress,
+		types.ArbAggregatorAddress,
+		impostor,
+		big.NewInt(0),
+		false,
+		1000000,
+		evm,
+	)
+	if err == nil {
+		Fail(t, "an impostor changed the fee collector")
+	}
+
+	solErr := aggregatorABI.Errors["NotAuthorizedFeeCollector"]
+	if len(output) < 4 || !bytes.Equal(output[:4], solErr.ID[:4]) {
+		Fail(t, "wrong revert selector", output)
+	}
+	args, err := solErr.Inputs.Unpack(output[4:])
+	Require(t, err)
+	if args[0].(common.Address) != impostor || args[1].(common.Address) != l1pricing.BatchPosterAddress {
+		Fail(t, "wrong revert arguments", args)
+	}
+}
+
+func TestFeeCollectorLookups(t *testing.T) {
+	evm := newMockEVMForTesting()
+	//nolint:errcheck
+	agg := Precompiles()[types.ArbAggregatorAddress].Precompile().implementer.Interface().(*ArbAggregator)
+	ownerCtx := testContext(common.Address{}, evm)
+	if ownerCtx.State.ArbOSVersion() < 31 {
+		Require(t, ownerCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	Require(t, ArbDebug{}.BecomeChainOwner(ownerCtx, evm))
+
+	posterA := l1pricing.BatchPosterAddress
+	posterB := common.BytesToAddress(crypto.Keccak256([]byte{3})[:20])
+	collectorX := common.BytesToAddress(crypto.Keccak256([]byte{4})[:20])
+	collectorY := common.BytesToAddress(crypto.Keccak256([]byte{5})[:20])
+	Require(t, agg.AddBatchPoster(ownerCtx, evm, posterB))
+	Require(t, agg.SetFeeCollector(ownerCtx, evm, posterA, collectorX))
+	Require(t, agg.SetFeeCollector(ownerCtx, evm, posterB, collectorX))
+
+	check := func(collectorOfB addr, payingX, payingY []addr) {
+		t.Helper()
+		collectors, err := agg.GetFeeCollectors(ownerCtx, evm, []addr{posterB, posterA})
+		Require(t, err)
+		if len(collectors) != 2 || collectors[0] != collectorOfB || collectors[1] != collectorX {
+			Fail(t, "wrong fee collectors", collectors)
+		}
+		for collector, expected := range map[addr][]addr{collectorX: payingX, collectorY: payingY} {
+			posters, err := agg.GetAggregatorsForCollector(ownerCtx, evm, collector)
+			Require(t, err)
+			if len(posters) != len(expected) {
+				Fail(t, "wrong posters for collector", collector, posters, expected)
+			}
+			for i := range expected {
+				if posters[i] != expected[i] {
+					Fail(t, "wrong posters for collector", collector, posters, expected)
+				}
+			}
+		}
+	}
+	check(collectorX, []addr{posterA, posterB}, []addr{})
+
+	// reassigning moves posterB from X's set to Y's
+	Require(t, agg.SetFeeCollector(ownerCtx, evm, posterB, collectorY))
+	check(collectorY, []addr{posterA}, []addr{posterB})
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/offchainlabs/nitro/arbos/util"
+	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
 // All calls to this precompile are authorized by the DebugPrecompile wrapper,
@@ -56,6 +58,37 @@ func (con ArbDebug) BecomeChainOwner(c ctx, evm mech) error {
 	return c.State.ChainOwners().Add(c.caller)
 }
 
+// SetNonce overwrites an account's nonce
+func (con ArbDebug) SetNonce(c ctx, evm mech, account addr, nonce uint64) error {
+	evm.StateDB.SetNonce(account, nonce)
+	return nil
+}
+
+// SetBalance overwrites an account's balance, minting or burning the difference
+func (con ArbDebug) SetBalance(c ctx, evm mech, account addr, balance huge) error {
+	if balance.Sign() < 0 {
+		return errors.New("balance cannot be negative")
+	}
+	current := evm.StateDB.GetBalance(account).ToBig()
+	if balance.Cmp(current) >= 0 {
+		util.MintBalance(&account, arbmath.BigSub(balance, current), evm, util.TracingDuringEVM, "debug")
+		return nil
+	}
+	return util.BurnBalance(&account, arbmath.BigSub(current, balance), evm, util.TracingDuringEVM, "debug")
+}
+
+// SetCode overwrites an account's code
+func (con ArbDebug) SetCode(c ctx, evm mech, account addr, code []byte) error {
+	evm.StateDB.SetCode(account, code)
+	return nil
+}
+
+// SetStorage overwrites one of an account's storage slots
+func (con ArbDebug) SetStorage(c ctx, evm mech, account addr, key bytes32, value bytes32) error {
+	evm.StateDB.SetState(account, key, value)
+	return nil
+}
+
 // Halts the chain by panicking in the STF
 func (con ArbDebug) Panic(c ctx, evm mech) error {
 	panic("called ArbDebug's debug-only Panic method")
@@ -0,0 +1,78 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package precompiles
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	templates "github.com/offchainlabs/nitro/solgen/go/precompilesgen"
+	"github.com/offchainlabs/nitro/util/testhelpers"
+)
+
+func TestArbDebugStateSetters(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	debugABI, err := templates.ArbDebugMetaData.GetAbi()
+	Require(t, err)
+	call := func(method string, args ...interface{}) error {
+		calldata, err := debugABI.Pack(method, args...)
+		Require(t, err)
+		_, _, err = Precompiles()[types.ArbDebugAddress].Call(
+			calldata,
+			types.ArbDebugAddress,
+			types.ArbDebugAddress,
+			common.Address{},
+			big.NewInt(0),
+			false,
+			1000000,
+			evm,
+		)
+		return err
+	}
+
+	account := testhelpers.RandomAddress()
+	code := []byte{byte(vm.PUSH1), 0x01, byte(vm.STOP)}
+	key := common.HexToHash("0x0102")
+	value := common.HexToHash("0x0304")
+
+	Require(t, call("setNonce", account, uint64(7)))
+	Require(t, call("setBalance", account, big.NewInt(1e18)))
+	Require(t, call("setCode", account, code))
+	Require(t, call("setStorage", account, key, value))
+	if nonce := evm.StateDB.GetNonce(account); nonce != 7 {
+		Fail(t, "wrong nonce", nonce)
+	}
+	if balance := evm.StateDB.GetBalance(account).ToBig(); balance.Cmp(big.NewInt(1e18)) != 0 {
+		Fail(t, "wrong balance", balance)
+	}
+	if !bytes.Equal(evm.StateDB.GetCode(account), code) {
+		Fail(t, "wrong code", evm.StateDB.GetCode(account))
+	}
+	if stored := evm.StateDB.GetState(account, key); stored != value {
+		Fail(t, "wrong storage", stored)
+	}
+
+	// lowering the balance burns the difference
+	Require(t, call("setBalance", account, big.NewInt(5)))
+	if balance := evm.StateDB.GetBalance(account).ToBig(); balance.Cmp(big.NewInt(5)) != 0 {
+		Fail(t, "wrong balance", balance)
+	}
+
+	evm.ChainConfig().ArbitrumChainParams.AllowDebugPrecompiles = false
+	if call("setNonce", account, uint64(8)) == nil {
+		Fail(t, "debug setter worked without debug precompiles")
+	}
+	if nonce := evm.StateDB.GetNonce(account); nonce != 7 {
+		Fail(t, "disabled setter changed the nonce", nonce)
+	}
+}
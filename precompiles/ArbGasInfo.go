@@ -140,9 +140,13 @@ func (con ArbGasInfo) GetPricesInArbGas(c ctx, evm mech) (huge, huge, huge, erro
 }
 
 // GetGasAccountingParams gets the rollup's speed limit, pool size, and tx gas limit
+// Nitro has no separate gas pool, so the per-block gas limit is reported as the pool size
 func (con ArbGasInfo) GetGasAccountingParams(c ctx, evm mech) (huge, huge, huge, error) {
 	l2pricing := c.State.L2PricingState()
-	speedLimit, _ := l2pricing.SpeedLimitPerSecond()
+	speedLimit, err := l2pricing.SpeedLimitPerSecond()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	maxTxGasLimit, err := l2pricing.PerBlockGasLimit()
 	return arbmath.UintToBig(speedLimit), arbmath.UintToBig(maxTxGasLimit), arbmath.UintToBig(maxTxGasLimit), err
 }
@@ -157,6 +161,20 @@ func (con ArbGasInfo) GetL1BaseFeeEstimate(c ctx, evm mech) (huge, error) {
 	return c.State.L1PricingState().PricePerUnit()
 }
 
+// GetL1PricingSnapshot gets the default aggregator, L1 basefee estimate, L1 reward rate, and ArbOS version as of one block
+func (con ArbGasInfo) GetL1PricingSnapshot(c ctx, evm mech) (addr, huge, huge, uint64, error) {
+	l1p := c.State.L1PricingState()
+	pricePerUnit, err := l1p.PricePerUnit()
+	if err != nil {
+		return addr{}, nil, nil, 0, err
+	}
+	rewardRate, err := l1p.PerUnitReward()
+	if err != nil {
+		return addr{}, nil, nil, 0, err
+	}
+	return l1pricing.BatchPosterAddress, pricePerUnit, arbmath.UintToBig(rewardRate), c.State.ArbOSVersion(), nil
+}
+
 // GetL1BaseFeeEstimateInertia gets how slowly ArbOS updates its estimate of the L1 basefee
 func (con ArbGasInfo) GetL1BaseFeeEstimateInertia(c ctx, evm mech) (uint64, error) {
 	return c.State.L1PricingState().Inertia()
@@ -177,6 +195,11 @@ func (con ArbGasInfo) GetL1GasPriceEstimate(c ctx, evm mech) (huge, error) {
 	return con.GetL1BaseFeeEstimate(c, evm)
 }
 
+// GetMinRedeemDonation gets the least gas a manual redeem must be able to donate to its retry, or 0 if there's no floor
+func (con ArbGasInfo) GetMinRedeemDonation(c ctx, evm mech) (uint64, error) {
+	return c.State.RetryableState().MinRedeemDonation()
+}
+
 // GetCurrentTxL1GasFees gets the fee paid to the aggregator for posting this tx
 func (con ArbGasInfo) GetCurrentTxL1GasFees(c ctx, evm mech) (huge, error) {
 	return c.txProcessor.PosterFee, nil
@@ -187,6 +210,11 @@ func (con ArbGasInfo) GetGasBacklog(c ctx, evm mech) (uint64, error) {
 	return c.State.L2PricingState().GasBacklog()
 }
 
+// GetSpeedLimitPerSecond gets the gas per second the L2 basefee targets before backlog builds up
+func (con ArbGasInfo) GetSpeedLimitPerSecond(c ctx, evm mech) (uint64, error) {
+	return c.State.L2PricingState().SpeedLimitPerSecond()
+}
+
 // GetPricingInertia gets how slowly ArbOS updates the L2 basefee in response to backlogged gas
 func (con ArbGasInfo) GetPricingInertia(c ctx, evm mech) (uint64, error) {
 	return c.State.L2PricingState().PricingInertia()
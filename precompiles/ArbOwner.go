@@ -12,6 +12,7 @@ import (
 
 	"github.com/offchainlabs/nitro/arbos/l1pricing"
 	"github.com/offchainlabs/nitro/arbos/programs"
+	"github.com/offchainlabs/nitro/arbos/retryables"
 	"github.com/offchainlabs/nitro/util/arbmath"
 	am "github.com/offchainlabs/nitro/util/arbmath"
 
@@ -112,8 +113,13 @@ func (con ArbOwner) SetInfraFeeAccount(c ctx, evm mech, newNetworkFeeAccount add
 	return c.State.SetInfraFeeAccount(newNetworkFeeAccount)
 }
 
-// ScheduleArbOSUpgrade to the requested version at the requested timestamp
+// ScheduleArbOSUpgrade to the requested version at the requested timestamp.
+// Scheduling version 0 cancels any pending upgrade.
 func (con ArbOwner) ScheduleArbOSUpgrade(c ctx, evm mech, newVersion uint64, timestamp uint64) error {
+	currentVersion := c.State.ArbOSVersion()
+	if currentVersion >= 31 && newVersion != 0 && newVersion < currentVersion {
+		return fmt.Errorf("cannot schedule a downgrade from ArbOS version %v to %v", currentVersion, newVersion)
+	}
 	return c.State.ScheduleArbOSUpgrade(newVersion, timestamp)
 }
 
@@ -149,6 +155,98 @@ func (con ArbOwner) SetBrotliCompressionLevel(c ctx, evm mech, level uint64) err
 	return c.State.SetBrotliCompressionLevel(level)
 }
 
+// SetRetryableRoundingPolicy sets how retryable rent charges are rounded
+func (con ArbOwner) SetRetryableRoundingPolicy(c ctx, evm mech, policy uint64) error {
+	return c.State.RetryableState().SetRoundingPolicy(policy)
+}
+
+// SetMaxRedeemGas sets the most gas Redeem will donate to a retry (0 disables the cap)
+func (con ArbOwner) SetMaxRedeemGas(c ctx, evm mech, gas uint64) error {
+	if gas != 0 && gas < params.TxGas {
+		return errors.New("max redeem gas must be zero or at least the intrinsic tx gas")
+	}
+	return c.State.RetryableState().SetMaxRedeemGas(gas)
+}
+
+// SetRetryableMaxTries sets the number of redeem attempts a retryable may have (0 means unlimited)
+func (con ArbOwner) SetRetryableMaxTries(c ctx, evm mech, tries uint64) error {
+	return c.State.RetryableState().SetMaxTries(tries)
+}
+
+// SetRetryableMaxTriesPolicy sets whether exhausted retryables reject redeems (0) or are cancelled (1)
+func (con ArbOwner) SetRetryableMaxTriesPolicy(c ctx, evm mech, policy uint64) error {
+	return c.State.RetryableState().SetMaxTriesPolicy(policy)
+}
+
+// SetRetryableLifetime sets the length of a retryable's lifetime period, in seconds
+func (con ArbOwner) SetRetryableLifetime(c ctx, evm mech, lifetime uint64) error {
+	return c.State.RetryableState().SetLifetime(lifetime)
+}
+
+// SetRetryableMinRedeemDonation sets the least gas a manual redeem may donate to its retry, or 0 for no floor
+func (con ArbOwner) SetRetryableMinRedeemDonation(c ctx, evm mech, gas uint64) error {
+	return c.State.RetryableState().SetMinRedeemDonation(gas)
+}
+
+// SetRetryableRedeemChargePerWord sets the gas a redeem burns per word of the ticket's size, or 0 for the default
+func (con ArbOwner) SetRetryableRedeemChargePerWord(c ctx, evm mech, charge uint64) error {
+	return c.State.RetryableState().SetRedeemChargePerWord(charge)
+}
+
+// SetRetryableCancelGracePeriod sets how long a pending redeem holds off canceling its ticket, or 0 for no time limit
+func (con ArbOwner) SetRetryableCancelGracePeriod(c ctx, evm mech, period uint64) error {
+	return c.State.RetryableState().SetCancelGracePeriod(period)
+}
+
+// SetRetryableMinKeepaliveInterval sets the fewest seconds allowed between a ticket's keepalives, or 0 to allow any
+func (con ArbOwner) SetRetryableMinKeepaliveInterval(c ctx, evm mech, interval uint64) error {
+	return c.State.RetryableState().SetMinKeepaliveInterval(interval)
+}
+
+// AddManualRedeemOnlyDestination disables the auto-redeem at submission for retryables to the destination
+func (con ArbOwner) AddManualRedeemOnlyDestination(c ctx, evm mech, destination addr) error {
+	return c.State.RetryableState().ManualRedeemOnly().Add(destination)
+}
+
+// RemoveManualRedeemOnlyDestination re-enables the auto-redeem at submission for retryables to the destination
+func (con ArbOwner) RemoveManualRedeemOnlyDestination(c ctx, evm mech, destination addr) error {
+	destinations := c.State.RetryableState().ManualRedeemOnly()
+	member, err := destinations.IsMember(destination)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return errors.New("tried to remove a destination that isn't manual-redeem-only")
+	}
+	return destinations.Remove(destination, c.State.ArbOSVersion())
+}
+
+// ExportRetryable serializes a ticket's full state so it can be migrated to another chain
+func (con ArbOwner) ExportRetryable(c ctx, evm mech, ticketId bytes32) ([]byte, error) {
+	retryable, err := c.State.RetryableState().OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return nil, err
+	}
+	if retryable == nil {
+		return nil, errors.New("ticketId not found")
+	}
+	return retryable.Export()
+}
+
+// ImportRetryable recreates an exported ticket, whose escrow must already hold its callvalue
+func (con ArbOwner) ImportRetryable(c ctx, evm mech, data []byte) error {
+	export, err := retryables.DecodeRetryableExport(data)
+	if err != nil {
+		return err
+	}
+	escrow := retryables.RetryableEscrowAddress(export.Id)
+	if evm.StateDB.GetBalance(escrow).ToBig().Cmp(export.Callvalue) < 0 {
+		return errors.New("retryable escrow must be funded before import")
+	}
+	_, err = c.State.RetryableState().ImportRetryable(export, evm.Context.Time)
+	return err
+}
+
 func (con ArbOwner) ReleaseL1PricerSurplusFunds(c ctx, evm mech, maxWeiToRelease huge) (huge, error) {
 	balance := evm.StateDB.GetBalance(l1pricing.L1PricerFundsPoolAddress)
 	l1p := c.State.L1PricingState()
@@ -228,3 +228,62 @@ func TestArbInfraFeeAccount(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestGasBacklogAndSpeedLimit(t *testing.T) {
+	evm := newMockEVMForTesting()
+	caller := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
+	callCtx := testContext(caller, evm)
+	Require(t, callCtx.State.ChainOwners().Add(caller))
+	prec := &ArbOwner{}
+	gasInfo := &ArbGasInfo{}
+
+	pricing := callCtx.State.L2PricingState()
+	Require(t, pricing.SetGasBacklog(123456))
+	backlog, err := gasInfo.GetGasBacklog(callCtx, evm)
+	Require(t, err)
+	if backlog != 123456 {
+		Fail(t, "wrong backlog", backlog)
+	}
+
+	Require(t, prec.SetSpeedLimit(callCtx, evm, 9000000))
+	limit, err := gasInfo.GetSpeedLimitPerSecond(callCtx, evm)
+	Require(t, err)
+	if limit != 9000000 {
+		Fail(t, "wrong speed limit", limit)
+	}
+}
+
+func TestScheduleArbOSUpgrade(t *testing.T) {
+	evm := newMockEVMForTesting()
+	caller := common.BytesToAddress(crypto.Keccak256([]byte{})[:20])
+	callCtx := testContext(caller, evm)
+	if callCtx.State.ArbOSVersion() < 31 {
+		Require(t, callCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	prec := &ArbOwner{}
+	precPublic := &ArbOwnerPublic{}
+
+	Require(t, prec.ScheduleArbOSUpgrade(callCtx, evm, 32, 1234))
+	version, timestamp, err := precPublic.GetScheduledUpgrade(callCtx, evm)
+	Require(t, err)
+	if version != 32 || timestamp != 1234 {
+		Fail(t, "wrong scheduled upgrade", version, timestamp)
+	}
+
+	if err := prec.ScheduleArbOSUpgrade(callCtx, evm, 30, 5678); err == nil {
+		Fail(t, "scheduled a downgrade")
+	}
+	version, timestamp, err = precPublic.GetScheduledUpgrade(callCtx, evm)
+	Require(t, err)
+	if version != 32 || timestamp != 1234 {
+		Fail(t, "a rejected downgrade changed the scheduled upgrade", version, timestamp)
+	}
+
+	// scheduling version 0 cancels the upgrade
+	Require(t, prec.ScheduleArbOSUpgrade(callCtx, evm, 0, 0))
+	version, _, err = precPublic.GetScheduledUpgrade(callCtx, evm)
+	Require(t, err)
+	if version != 0 {
+		Fail(t, "upgrade wasn't cancelled", version)
+	}
+}
@@ -5,12 +5,16 @@ package precompiles
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"math/big"
+	"math/bits"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/offchainlabs/nitro/arbos/l1pricing"
 	"github.com/offchainlabs/nitro/arbos/retryables"
 	"github.com/offchainlabs/nitro/arbos/storage"
 	"github.com/offchainlabs/nitro/arbos/util"
@@ -28,15 +32,22 @@ type ArbRetryableTx struct {
 	RedeemScheduledGasCost  func(bytes32, bytes32, uint64, uint64, addr, huge, huge) (uint64, error)
 	CanceledGasCost         func(bytes32) (uint64, error)
 
-	// deprecated event
-	Redeemed        func(ctx, mech, bytes32) error
-	RedeemedGasCost func(bytes32) (uint64, error)
+	// emitted after each retry attempt with the gas it used and whether it succeeded
+	Redeemed        func(ctx, mech, bytes32, uint64, bool) error
+	RedeemedGasCost func(bytes32, uint64, bool) (uint64, error)
+
+	BeneficiaryTransferred        func(ctx, mech, bytes32, addr, addr) error
+	BeneficiaryTransferredGasCost func(bytes32, addr, addr) (uint64, error)
 
 	NoTicketWithIDError func() error
 	NotCallableError    func() error
+	TicketExpiredError  func() error
+	NotBeneficiaryError func(addr) error
 }
 
 var ErrSelfModifyingRetryable = errors.New("retryable cannot modify itself")
+var ErrZeroTicketId = errors.New("zero ticket id")
+var ErrRedeemPending = errors.New("cannot cancel a retryable while a redeem is pending")
 
 func (con ArbRetryableTx) oldNotFoundError(c ctx) error {
 	if c.State.ArbOSVersion() >= 3 {
@@ -45,66 +56,209 @@ func (con ArbRetryableTx) oldNotFoundError(c ctx) error {
 	return errors.New("ticketId not found")
 }
 
+// checkTicketId rejects the zero ticket id from ArbOS 31, since no real ticket has it
+func (con ArbRetryableTx) checkTicketId(c ctx, ticketId bytes32) error {
+	if c.State.ArbOSVersion() >= 31 && ticketId == (bytes32{}) {
+		return ErrZeroTicketId
+	}
+	return nil
+}
+
+// notFoundError explains why a ticket couldn't be opened, distinguishing expired tickets from ArbOS 31 on
+func (con ArbRetryableTx) notFoundError(c ctx, ticketId bytes32, notFound error) error {
+	if c.State.ArbOSVersion() >= 31 {
+		expired, err := c.State.RetryableState().OpenRetryable(ticketId, 0)
+		if err != nil {
+			return err
+		}
+		if expired != nil {
+			return con.TicketExpiredError()
+		}
+	}
+	return notFound
+}
+
 // Redeem schedules an attempt to redeem the retryable, donating all of the call's gas to the redeem attempt
 func (con ArbRetryableTx) Redeem(c ctx, evm mech, ticketId bytes32) (bytes32, error) {
+	return con.scheduleRedeem(c, evm, ticketId, c.caller, math.MaxUint64, 0)
+}
+
+// RedeemAtGasPrice schedules a redeem like Redeem, reverting if the retry would be priced above maxGasPrice.
+// Retries always run at the current basefee, so there is never a price difference to refund.
+func (con ArbRetryableTx) RedeemAtGasPrice(c ctx, evm mech, ticketId bytes32, maxGasPrice huge) (bytes32, error) {
+	if maxGasPrice.Cmp(evm.Context.BaseFee) < 0 {
+		return bytes32{}, fmt.Errorf("max gas price %v is below the current basefee %v", maxGasPrice, evm.Context.BaseFee)
+	}
+	return con.scheduleRedeem(c, evm, ticketId, c.caller, math.MaxUint64, 0)
+}
+
+// RedeemWithGasLimit schedules a redeem like Redeem, but donates at most gasLimit and leaves the rest with the caller
+func (con ArbRetryableTx) RedeemWithGasLimit(c ctx, evm mech, ticketId bytes32, gasLimit uint64) (bytes32, error) {
+	if gasLimit == 0 {
+		return bytes32{}, errors.New("cannot redeem a retryable with no gas")
+	}
+	return con.scheduleRedeem(c, evm, ticketId, c.caller, gasLimit, 0)
+}
+
+// RedeemTo schedules a redeem like Redeem, crediting the retry's gas refund to redeemer instead of the caller.
+// Only the ticket's beneficiary or the batch poster may redeem on another's behalf.
+func (con ArbRetryableTx) RedeemTo(c ctx, evm mech, ticketId bytes32, redeemer addr) (bytes32, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return bytes32{}, err
+	}
+	retryable, err := c.State.RetryableState().OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return hash{}, err
+	}
+	if retryable == nil {
+		return hash{}, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	beneficiary, err := retryable.Beneficiary()
+	if err != nil {
+		return hash{}, err
+	}
+	if c.caller != beneficiary && c.caller != l1pricing.BatchPosterAddress {
+		return hash{}, errors.New("only the beneficiary or the batch poster may redeem on behalf of another")
+	}
+	return con.scheduleRedeem(c, evm, ticketId, redeemer, math.MaxUint64, 0)
+}
+
+// openForRedeem opens the ticket a redeem would retry, charging for the storage the redeem rewrites
+func (con ArbRetryableTx) openForRedeem(c ctx, evm mech, ticketId bytes32) (*retryables.Retryable, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, err
+	}
 	if c.txProcessor.CurrentRetryable != nil && ticketId == *c.txProcessor.CurrentRetryable {
-		return bytes32{}, ErrSelfModifyingRetryable
+		return nil, ErrSelfModifyingRetryable
 	}
 	retryableState := c.State.RetryableState()
 	byteCount, err := retryableState.RetryableSizeBytes(ticketId, evm.Context.Time)
 	if err != nil {
-		return hash{}, err
+		return nil, err
+	}
+	chargePerWord, err := retryableState.RedeemChargePerWord(c.State.ArbOSVersion())
+	if err != nil {
+		return nil, err
 	}
 	writeBytes := arbmath.WordsForBytes(byteCount)
-	if err := c.Burn(params.SloadGas * writeBytes); err != nil {
-		return hash{}, err
+	if err := c.burnLabeled("redeem:size", chargePerWord*writeBytes); err != nil {
+		return nil, err
 	}
 
 	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
 	if err != nil {
-		return hash{}, err
+		return nil, err
 	}
 	if retryable == nil {
-		return hash{}, con.oldNotFoundError(c)
+		return nil, con.notFoundError(c, ticketId, con.oldNotFoundError(c))
 	}
-	nextNonce, err := retryable.IncrementNumTries()
-	if err != nil {
-		return hash{}, err
-	}
-	nonce := nextNonce - 1
+	return retryable, nil
+}
 
+// makeRedeemTx builds the retry a redeem with the given nonce schedules, leaving its gas to be filled in.
+// It also returns the max refund the retry is issued with.
+func makeRedeemTx(
+	evm mech, retryable *retryables.Retryable, ticketId bytes32, nonce uint64, redeemer addr,
+) (*types.ArbitrumRetryTx, huge, error) {
 	maxRefund := new(big.Int).Exp(common.Big2, common.Big256, nil)
 	maxRefund.Sub(maxRefund, common.Big1)
 	retryTxInner, err := retryable.MakeTx(
 		evm.ChainConfig().ChainID,
 		nonce,
 		evm.Context.BaseFee,
-		0, // will fill this in below
+		0, // filled in once the donation is known
 		ticketId,
-		c.caller,
+		redeemer,
 		maxRefund,
 		common.Big0,
 	)
-	if err != nil {
-		return hash{}, err
+	return retryTxInner, maxRefund, err
+}
+
+// redeemDonation works out how much of the call's remaining gas a redeem donates, up to maxDonation,
+// after setting aside what the redeem spends once it has donated plus reservedGas
+func (con ArbRetryableTx) redeemDonation(c ctx, maxDonation, reservedGas uint64) (uint64, error) {
+	retryableState := c.State.RetryableState()
+	if c.State.ArbOSVersion() >= 31 {
+		// any gas above the cap is left with the caller
+		maxRedeemGas, err := retryableState.MaxRedeemGas()
+		if err != nil {
+			return 0, err
+		}
+		if maxRedeemGas != 0 {
+			maxDonation = arbmath.MinInt(maxDonation, maxRedeemGas)
+		}
 	}
 
 	// figure out how much gas the event issuance will cost, and reduce the donated gas amount in the event
 	//     by that much, so that we'll donate the correct amount of gas
 	eventCost, err := con.RedeemScheduledGasCost(hash{}, hash{}, 0, 0, addr{}, common.Big0, common.Big0)
 	if err != nil {
-		return hash{}, err
+		return 0, err
 	}
 	// Result is 32 bytes long which is 1 word
 	gasCostToReturnResult := params.CopyGas
 	gasPoolUpdateCost := storage.StorageReadCost + storage.StorageWriteCost
-	futureGasCosts := eventCost + gasCostToReturnResult + gasPoolUpdateCost
+	futureGasCosts := eventCost + gasCostToReturnResult + gasPoolUpdateCost + reservedGas
 	if c.gasLeft < futureGasCosts {
-		return hash{}, c.Burn(futureGasCosts) // this will error
+		return 0, c.Burn(futureGasCosts) // this will error
 	}
-	gasToDonate := c.gasLeft - futureGasCosts
+	gasToDonate := arbmath.MinInt(c.gasLeft-futureGasCosts, maxDonation)
 	if gasToDonate < params.TxGas {
-		return hash{}, errors.New("not enough gas to run redeem attempt")
+		return 0, errors.New("not enough gas to run redeem attempt")
+	}
+	if c.State.ArbOSVersion() >= 31 {
+		minDonation, err := retryableState.MinRedeemDonation()
+		if err != nil {
+			return 0, err
+		}
+		if gasToDonate < minDonation {
+			return 0, errors.New("insufficient gas to schedule redeem")
+		}
+	}
+	return gasToDonate, nil
+}
+
+// scheduleRedeem schedules a redeem attempt on behalf of redeemer, donating up to maxDonation of the call's
+// remaining gas while leaving reservedGas for work the caller does afterward
+func (con ArbRetryableTx) scheduleRedeem(
+	c ctx, evm mech, ticketId bytes32, redeemer addr, maxDonation, reservedGas uint64,
+) (bytes32, error) {
+	retryable, err := con.openForRedeem(c, evm, ticketId)
+	if err != nil {
+		return hash{}, err
+	}
+	retryableState := c.State.RetryableState()
+	if c.State.ArbOSVersion() >= 31 {
+		exhausted, err := con.handleMaxTries(c, evm, ticketId, retryable)
+		if err != nil || exhausted {
+			return hash{}, err
+		}
+	}
+	nextNonce, err := retryable.IncrementNumTries()
+	if err != nil {
+		return hash{}, err
+	}
+	nonce := nextNonce - 1
+	if c.State.ArbOSVersion() >= 31 {
+		if err := retryable.RecordRedeem(redeemer, nonce); err != nil {
+			return hash{}, err
+		}
+		if err := retryableState.IncrementRedeemsScheduled(); err != nil {
+			return hash{}, err
+		}
+		if err := retryable.AddPendingRedeem(evm.Context.Time); err != nil {
+			return hash{}, err
+		}
+	}
+
+	retryTxInner, maxRefund, err := makeRedeemTx(evm, retryable, ticketId, nonce, redeemer)
+	if err != nil {
+		return hash{}, err
+	}
+	gasToDonate, err := con.redeemDonation(c, maxDonation, reservedGas)
+	if err != nil {
+		return hash{}, err
 	}
 
 	// fix up the gas in the retry
@@ -113,7 +267,7 @@ func (con ArbRetryableTx) Redeem(c ctx, evm mech, ticketId bytes32) (bytes32, er
 	retryTx := types.NewTx(retryTxInner)
 	retryTxHash := retryTx.Hash()
 
-	err = con.RedeemScheduled(c, evm, ticketId, retryTxHash, nonce, gasToDonate, c.caller, maxRefund, common.Big0)
+	err = con.RedeemScheduled(c, evm, ticketId, retryTxHash, nonce, gasToDonate, redeemer, maxRefund, common.Big0)
 	if err != nil {
 		return hash{}, err
 	}
@@ -121,7 +275,7 @@ func (con ArbRetryableTx) Redeem(c ctx, evm mech, ticketId bytes32) (bytes32, er
 	// To prepare for the enqueued retry event, we burn gas here, adding it back to the pool right before retrying.
 	// The gas payer for this tx will get a credit for the wei they paid for this gas when retrying.
 	// We burn as much gas as we can, leaving only enough to pay for copying out the return data.
-	if err := c.Burn(gasToDonate); err != nil {
+	if err := c.burnLabeled("redeem:donation", gasToDonate); err != nil {
 		return hash{}, err
 	}
 
@@ -130,30 +284,310 @@ func (con ArbRetryableTx) Redeem(c ctx, evm mech, ticketId bytes32) (bytes32, er
 	return retryTxHash, c.State.L2PricingState().AddToGasPool(arbmath.SaturatingCast[int64](gasToDonate))
 }
 
+// SimulateRedeem previews a Redeem by the caller without scheduling it, returning the retry's tx id and the gas it
+// would donate. The id commits to the donation, so it matches a Redeem that donates the same amount.
+func (con ArbRetryableTx) SimulateRedeem(c ctx, evm mech, ticketId bytes32) (bytes32, uint64, error) {
+	retryable, err := con.openForRedeem(c, evm, ticketId)
+	if err != nil {
+		return hash{}, 0, err
+	}
+	// the redeem's nonce is the try count before it increments it
+	nonce, err := retryable.NumTries()
+	if err != nil {
+		return hash{}, 0, err
+	}
+	retryTxInner, _, err := makeRedeemTx(evm, retryable, ticketId, nonce, c.caller)
+	if err != nil {
+		return hash{}, 0, err
+	}
+	gasToDonate, err := con.redeemDonation(c, math.MaxUint64, 0)
+	if err != nil {
+		return hash{}, 0, err
+	}
+	retryTxInner.Gas = gasToDonate
+	return types.NewTx(retryTxInner).Hash(), gasToDonate, nil
+}
+
+// EstimateBatchRedeemOverhead estimates the gas ProcessTickets burns to schedule redeems of the tickets, excluding donated gas.
+// This assumes no keepalives and that each storage write is of a nonzero value.
+func (con ArbRetryableTx) EstimateBatchRedeemOverhead(c ctx, evm mech, ticketIds []bytes32) (uint64, error) {
+	retryableState := c.State.RetryableState()
+	eventCost, err := con.RedeemScheduledGasCost(hash{}, hash{}, 0, 0, addr{}, common.Big0, common.Big0)
+	if err != nil {
+		return 0, err
+	}
+	maxTries, err := retryableState.MaxTries()
+	if err != nil {
+		return 0, err
+	}
+	chargePerWord, err := retryableState.RedeemChargePerWord(c.State.ArbOSVersion())
+	if err != nil {
+		return 0, err
+	}
+
+	// the ids are copied in and the tx ids copied out, each alongside two offsets and two lengths
+	words := uint64(4 + len(ticketIds))
+	overhead := 2 * params.CopyGas * words
+	for _, ticketId := range ticketIds {
+		retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+		if err != nil {
+			return 0, err
+		}
+		if retryable == nil {
+			return 0, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+		}
+		calldataSize, err := retryable.CalldataSize()
+		if err != nil {
+			return 0, err
+		}
+		byteCount, err := retryable.SizeBytes()
+		if err != nil {
+			return 0, err
+		}
+		overhead += chargePerWord * arbmath.WordsForBytes(byteCount)
+		overhead += scheduleRedeemStorageCost(calldataSize, maxTries != 0)
+		overhead += eventCost
+	}
+	return overhead, nil
+}
+
+// scheduleRedeemStorageCost counts the storage accesses scheduleRedeem makes for a ticket from ArbOS 31.
+// Keep this in step with scheduleRedeem, which makes them in this order.
+func scheduleRedeemStorageCost(calldataSize uint64, limitsTries bool) uint64 {
+	reads := uint64(2) // RetryableSizeBytes opens the ticket and sizes its calldata
+	reads++            // RedeemChargePerWord
+	reads++            // OpenRetryable
+	reads++            // handleMaxTries reads the limit
+	if limitsTries {
+		reads++ // and then the ticket's number of tries
+	}
+	writes := uint64(0)
+	reads, writes = reads+1, writes+1 // IncrementNumTries
+	reads, writes = reads+1, writes+2 // RecordRedeem stores the entry and the new count
+	reads, writes = reads+1, writes+1 // IncrementRedeemsScheduled
+	reads, writes = reads+1, writes+2 // AddPendingRedeem counts the redeem and stores when it was scheduled
+	reads += 5 + calldataSize/32      // MakeTx reads the ticket's fields and its calldata
+	reads++                           // MaxRedeemGas
+	reads++                           // MinRedeemDonation
+	reads, writes = reads+1, writes+1 // AddToGasPool
+	return reads*storage.StorageReadCost + writes*storage.StorageWriteCost
+}
+
+// handleMaxTries applies the max tries policy, returning true if the retryable was cancelled instead of redeemed
+func (con ArbRetryableTx) handleMaxTries(c ctx, evm mech, ticketId bytes32, retryable *retryables.Retryable) (bool, error) {
+	retryableState := c.State.RetryableState()
+	maxTries, err := retryableState.MaxTries()
+	if err != nil || maxTries == 0 {
+		return false, err
+	}
+	numTries, err := retryable.NumTries()
+	if err != nil || numTries < maxTries {
+		return false, err
+	}
+	policy, err := retryableState.MaxTriesPolicy()
+	if err != nil {
+		return false, err
+	}
+	if policy != retryables.MaxTriesCancel {
+		return false, errors.New("retryable has reached the maximum number of tries")
+	}
+	if _, err := retryableState.DeleteRetryable(ticketId, evm, util.TracingDuringEVM, c.State.ArbOSVersion()); err != nil {
+		return false, err
+	}
+	if err := retryableState.IncrementRetryablesCancelled(); err != nil {
+		return false, err
+	}
+	return true, con.Canceled(c, evm, ticketId)
+}
+
+// GetMaxTries gets the number of redeem attempts a ticket may have, or 0 if unlimited
+func (con ArbRetryableTx) GetMaxTries(c ctx, evm mech) (uint64, error) {
+	return c.State.RetryableState().MaxTries()
+}
+
+// GetMaxTriesPolicy gets whether exhausted tickets reject redeems (0) or are cancelled (1)
+func (con ArbRetryableTx) GetMaxTriesPolicy(c ctx, evm mech) (uint64, error) {
+	return c.State.RetryableState().MaxTriesPolicy()
+}
+
+// ProcessTickets extends the lifetimes of keepaliveIds, then schedules redeems of redeemIds, splitting the remaining gas evenly among them
+func (con ArbRetryableTx) ProcessTickets(c ctx, evm mech, redeemIds []bytes32, keepaliveIds []bytes32) ([]bytes32, []huge, error) {
+	timeouts, err := con.KeepaliveBatch(c, evm, keepaliveIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// reserve enough gas to return both arrays: two offsets, two lengths, and the elements
+	resultWords := uint64(4 + len(redeemIds) + len(keepaliveIds))
+	returnCost := params.CopyGas * resultWords
+	if c.gasLeft < returnCost {
+		return nil, nil, c.Burn(returnCost) // this will error
+	}
+	redeemTxIds := make([]bytes32, 0, len(redeemIds))
+	for i, ticketId := range redeemIds {
+		share := (c.gasLeft - returnCost) / uint64(len(redeemIds)-i)
+		retryTxHash, err := con.scheduleRedeem(c, evm, ticketId, c.caller, share, returnCost)
+		if err != nil {
+			return nil, nil, err
+		}
+		redeemTxIds = append(redeemTxIds, retryTxHash)
+	}
+	return redeemTxIds, timeouts, nil
+}
+
 // GetLifetime gets the default lifetime period a retryable has at creation
 func (con ArbRetryableTx) GetLifetime(c ctx, evm mech) (huge, error) {
-	return big.NewInt(retryables.RetryableLifetimeSeconds), nil
+	lifetime, err := c.State.RetryableState().Lifetime(c.State.ArbOSVersion())
+	return arbmath.UintToBig(lifetime), err
+}
+
+// GetSubmissionPrice gets the base submission fee and the fee per byte of calldata, so that a retryable with
+// dataSize bytes of calldata costs base + perByte*dataSize. The fee actually charged uses the L1 basefee of the submitting message, which this approximates with ArbOS's estimate.
+func (con ArbRetryableTx) GetSubmissionPrice(c ctx, evm mech, dataSize uint64) (huge, huge, error) {
+	l1BaseFee, err := c.State.L1PricingState().PricePerUnit()
+	if err != nil {
+		return nil, nil, err
+	}
+	base := retryables.RetryableSubmissionFee(0, l1BaseFee)
+	perByte := arbmath.BigSub(retryables.RetryableSubmissionFee(1, l1BaseFee), base)
+	return base, perByte, nil
+}
+
+// GetRentRateWei gets the cost of keeping a retryable alive, in wei per byte per second, at the current basefee
+func (con ArbRetryableTx) GetRentRateWei(c ctx, evm mech) (huge, error) {
+	// Keepalive charges SstoreSetGas / 100 per word for each lifetime period
+	retryableState := c.State.RetryableState()
+	policy, err := retryableState.RoundingPolicy()
+	if err != nil {
+		return nil, err
+	}
+	lifetime, err := retryableState.Lifetime(c.State.ArbOSVersion())
+	if err != nil {
+		return nil, err
+	}
+	weiPerWordPerLifetime := arbmath.BigMulByUint(evm.Context.BaseFee, params.SstoreSetGas)
+	return retryables.RoundChargeBig(policy, weiPerWordPerLifetime, arbmath.SaturatingUMul(100*32, lifetime)), nil
+}
+
+// GetRoundingPolicy gets the rounding policy applied to rent charges
+func (con ArbRetryableTx) GetRoundingPolicy(c ctx, evm mech) (uint64, error) {
+	return c.State.RetryableState().RoundingPolicy()
+}
+
+// GetRetryGasPrice gets the gas price a retry scheduled now would run at
+func (con ArbRetryableTx) GetRetryGasPrice(c ctx, evm mech) (huge, error) {
+	// Redeem sets the retry's gas fee cap to the current basefee
+	return new(big.Int).Set(evm.Context.BaseFee), nil
+}
+
+// GetMaxRedeemGas gets the most gas Redeem will donate to a retry, or 0 if uncapped
+func (con ArbRetryableTx) GetMaxRedeemGas(c ctx, evm mech) (uint64, error) {
+	return c.State.RetryableState().MaxRedeemGas()
 }
 
 // GetTimeout gets the timestamp for when ticket will expire
 func (con ArbRetryableTx) GetTimeout(c ctx, evm mech, ticketId bytes32) (huge, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return nil, err
+	}
+	if retryable == nil {
+		return nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	timeout, err := retryable.CalculateTimeout(c.State.ArbOSVersion())
+	if err != nil {
+		return nil, err
+	}
+	return arbmath.UintToBig(timeout), nil
+}
+
+// GetTimeouts gets the timestamp each ticket will expire at, in order, using 0 for tickets that are missing or expired
+func (con ArbRetryableTx) GetTimeouts(c ctx, evm mech, ticketIds []bytes32) ([]huge, error) {
+	retryableState := c.State.RetryableState()
+	timeouts := make([]huge, 0, len(ticketIds))
+	for _, ticketId := range ticketIds {
+		retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+		if err != nil {
+			return nil, err
+		}
+		if retryable == nil {
+			timeouts = append(timeouts, common.Big0)
+			continue
+		}
+		timeout, err := retryable.CalculateTimeout(c.State.ArbOSVersion())
+		if err != nil {
+			return nil, err
+		}
+		timeouts = append(timeouts, arbmath.UintToBig(timeout))
+	}
+	return timeouts, nil
+}
+
+// GetNumTries gets the number of redeem attempts scheduled for the ticket
+func (con ArbRetryableTx) GetNumTries(c ctx, evm mech, ticketId bytes32) (uint64, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return 0, err
+	}
 	retryableState := c.State.RetryableState()
 	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return 0, err
+	}
+	if retryable == nil {
+		return 0, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	return retryable.NumTries()
+}
+
+// GetTimeRemaining gets the seconds until the ticket expires, or 0 if it already has but hasn't been reaped
+func (con ArbRetryableTx) GetTimeRemaining(c ctx, evm mech, ticketId bytes32) (huge, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, 0)
 	if err != nil {
 		return nil, err
 	}
 	if retryable == nil {
-		return nil, con.NoTicketWithIDError()
+		return nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
 	}
-	timeout, err := retryable.CalculateTimeout()
+	timeout, err := retryable.CalculateTimeout(c.State.ArbOSVersion())
 	if err != nil {
 		return nil, err
 	}
-	return big.NewInt(int64(timeout)), nil
+	return arbmath.UintToBig(arbmath.SaturatingUSub(timeout, evm.Context.Time)), nil
+}
+
+// GetInitialTimeout gets the timestamp the ticket was set to expire at when it was created
+func (con ArbRetryableTx) GetInitialTimeout(c ctx, evm mech, ticketId bytes32) (huge, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return nil, err
+	}
+	if retryable == nil {
+		return nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	timeout, err := retryable.InitialTimeout()
+	if err != nil {
+		return nil, err
+	}
+	return arbmath.UintToBig(timeout), nil
 }
 
 // Keepalive adds one lifetime period to the ticket's expiry
 func (con ArbRetryableTx) Keepalive(c ctx, evm mech, ticketId bytes32) (huge, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, err
+	}
 
 	// charge for the expiry update
 	retryableState := c.State.RetryableState()
@@ -162,39 +596,336 @@ func (con ArbRetryableTx) Keepalive(c ctx, evm mech, ticketId bytes32) (huge, er
 		return nil, err
 	}
 	if nbytes == 0 {
-		return nil, con.oldNotFoundError(c)
+		return nil, con.notFoundError(c, ticketId, con.oldNotFoundError(c))
 	}
-	updateCost := arbmath.WordsForBytes(nbytes) * params.SstoreSetGas / 100
-	if err := c.Burn(updateCost); err != nil {
+	updateCost, err := con.keepaliveUpdateCost(c, nbytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.burnLabeled("keepalive:update", updateCost); err != nil {
 		return big.NewInt(0), err
 	}
 
+	lifetime, err := retryableState.Lifetime(c.State.ArbOSVersion())
+	if err != nil {
+		return nil, err
+	}
 	currentTime := evm.Context.Time
-	window := currentTime + retryables.RetryableLifetimeSeconds
-	newTimeout, err := retryableState.Keepalive(ticketId, currentTime, window, retryables.RetryableLifetimeSeconds)
+	window, overflow := bits.Add64(currentTime, lifetime, 0)
+	if overflow != 0 {
+		return big.NewInt(0), errors.New("retryable lifetime overflows the timeout")
+	}
+	newTimeout, err := retryableState.Keepalive(ticketId, currentTime, window, lifetime, c.State.ArbOSVersion())
 	if err != nil {
 		return big.NewInt(0), err
 	}
 
-	err = con.LifetimeExtended(c, evm, ticketId, big.NewInt(int64(newTimeout)))
-	return big.NewInt(int64(newTimeout)), err
+	if c.State.ArbOSVersion() >= 31 {
+		rent := arbmath.BigMulByUint(evm.Context.BaseFee, updateCost)
+		if err := retryableState.AddRentCollected(rent); err != nil {
+			return big.NewInt(0), err
+		}
+	}
+
+	err = con.LifetimeExtended(c, evm, ticketId, arbmath.UintToBig(newTimeout))
+	return arbmath.UintToBig(newTimeout), err
+}
+
+// keepaliveUpdateCost gets the gas Keepalive charges to extend a ticket of the given size
+func (con ArbRetryableTx) keepaliveUpdateCost(c ctx, nbytes uint64) (uint64, error) {
+	if c.State.ArbOSVersion() < 31 {
+		return arbmath.WordsForBytes(nbytes) * params.SstoreSetGas / 100, nil
+	}
+	policy, err := c.State.RetryableState().RoundingPolicy()
+	if err != nil {
+		return 0, err
+	}
+	return retryables.RoundCharge(policy, arbmath.WordsForBytes(nbytes)*params.SstoreSetGas, 100), nil
+}
+
+// GetKeepaliveCost quotes the gas Keepalive would charge for the ticket now, excluding its metered storage accesses
+func (con ArbRetryableTx) GetKeepaliveCost(c ctx, evm mech, ticketId bytes32) (huge, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, err
+	}
+	nbytes, err := c.State.RetryableState().RetryableSizeBytes(ticketId, evm.Context.Time)
+	if err != nil {
+		return nil, err
+	}
+	if nbytes == 0 {
+		return nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	updateCost, err := con.keepaliveUpdateCost(c, nbytes)
+	if err != nil {
+		return nil, err
+	}
+	eventCost, err := con.LifetimeExtendedGasCost(ticketId, common.Big0)
+	if err != nil {
+		return nil, err
+	}
+	return arbmath.UintToBig(arbmath.SaturatingUAdd(updateCost, eventCost)), nil
+}
+
+// KeepaliveBatch extends the lifetime of each ticket, reverting if any is missing
+func (con ArbRetryableTx) KeepaliveBatch(c ctx, evm mech, ticketIds []bytes32) ([]huge, error) {
+	timeouts := make([]huge, 0, len(ticketIds))
+	for _, ticketId := range ticketIds {
+		timeout, err := con.Keepalive(c, evm, ticketId)
+		if err != nil {
+			return nil, err
+		}
+		timeouts = append(timeouts, timeout)
+	}
+	return timeouts, nil
+}
+
+// GetTotalRentCollected gets the cumulative wei paid to extend retryable lifetimes
+func (con ArbRetryableTx) GetTotalRentCollected(c ctx, evm mech) (huge, error) {
+	return c.State.RetryableState().TotalRentCollected()
+}
+
+// GetKeepaliveCount gets the number of times the ticket's lifetime has been extended
+func (con ArbRetryableTx) GetKeepaliveCount(c ctx, evm mech, ticketId bytes32) (uint64, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return 0, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return 0, err
+	}
+	if retryable == nil {
+		return 0, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	return retryable.KeepaliveCount()
 }
 
 // GetBeneficiary gets the beneficiary of the ticket
 func (con ArbRetryableTx) GetBeneficiary(c ctx, evm mech, ticketId bytes32) (addr, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return addr{}, err
+	}
 	retryableState := c.State.RetryableState()
 	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
 	if err != nil {
 		return addr{}, err
 	}
 	if retryable == nil {
-		return addr{}, con.oldNotFoundError(c)
+		return addr{}, con.notFoundError(c, ticketId, con.oldNotFoundError(c))
 	}
 	return retryable.Beneficiary()
 }
 
+// GetBeneficiaries gets the beneficiary of each ticket, in order, using the zero address for missing or expired tickets
+func (con ArbRetryableTx) GetBeneficiaries(c ctx, evm mech, ticketIds []bytes32) ([]addr, error) {
+	retryableState := c.State.RetryableState()
+	beneficiaries := make([]addr, len(ticketIds))
+	for i, ticketId := range ticketIds {
+		retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+		if err != nil {
+			return nil, err
+		}
+		if retryable == nil {
+			continue
+		}
+		beneficiaries[i], err = retryable.Beneficiary()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return beneficiaries, nil
+}
+
+// GetRetryableData gets the ticket's sender, destination, callvalue, beneficiary, and calldata.
+// The max submission fee and fee refund address aren't stored, so they aren't available.
+func (con ArbRetryableTx) GetRetryableData(c ctx, evm mech, ticketId bytes32) (addr, addr, huge, addr, []byte, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return addr{}, addr{}, nil, addr{}, nil, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return addr{}, addr{}, nil, addr{}, nil, err
+	}
+	if retryable == nil {
+		return addr{}, addr{}, nil, addr{}, nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	from, err := retryable.From()
+	if err != nil {
+		return addr{}, addr{}, nil, addr{}, nil, err
+	}
+	to, err := retryable.To()
+	if err != nil {
+		return addr{}, addr{}, nil, addr{}, nil, err
+	}
+	callvalue, err := retryable.Callvalue()
+	if err != nil {
+		return addr{}, addr{}, nil, addr{}, nil, err
+	}
+	beneficiary, err := retryable.Beneficiary()
+	if err != nil {
+		return addr{}, addr{}, nil, addr{}, nil, err
+	}
+	calldata, err := retryable.Calldata()
+	if err != nil {
+		return addr{}, addr{}, nil, addr{}, nil, err
+	}
+	destination := addr{} // a nil destination means the retry creates a contract
+	if to != nil {
+		destination = *to
+	}
+	return from, destination, callvalue, beneficiary, calldata, nil
+}
+
+// GetTotalRetryableBytes gets the bytes of state held by live retryables created since ArbOS 31
+func (con ArbRetryableTx) GetTotalRetryableBytes(c ctx, evm mech) (uint64, error) {
+	return c.State.RetryableState().TotalStorageBytes()
+}
+
+// GetEscrowedValue gets the callvalue held in escrow for the ticket, which is refunded to the beneficiary if it's canceled
+func (con ArbRetryableTx) GetEscrowedValue(c ctx, evm mech, ticketId bytes32) (huge, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, err
+	}
+	retryable, err := c.State.RetryableState().OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return nil, err
+	}
+	if retryable == nil {
+		return nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	return retryable.Callvalue()
+}
+
+// GetRetryableSizeBytes gets the number of bytes of state the ticket occupies, which keepalives and redeems pay for
+func (con ArbRetryableTx) GetRetryableSizeBytes(c ctx, evm mech, ticketId bytes32) (uint64, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return 0, err
+	}
+	size, err := c.State.RetryableState().RetryableSizeBytes(ticketId, evm.Context.Time)
+	if err != nil {
+		return 0, err
+	}
+	if size == 0 {
+		return 0, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	return size, nil
+}
+
+// GetBeneficiaryHistory gets the ticket's most recent prior beneficiaries, oldest first
+func (con ArbRetryableTx) GetBeneficiaryHistory(c ctx, evm mech, ticketId bytes32) ([]addr, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return nil, err
+	}
+	if retryable == nil {
+		return nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	return retryable.BeneficiaryHistory()
+}
+
+// GetRedeemHistory gets the redeemer and sequence number of each of the ticket's most recent redeem attempts
+func (con ArbRetryableTx) GetRedeemHistory(c ctx, evm mech, ticketId bytes32) ([]addr, []uint64, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return nil, nil, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return nil, nil, err
+	}
+	if retryable == nil {
+		return nil, nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	return retryable.RedeemHistory()
+}
+
+// WasAutoRedeemed checks whether an auto-redeem was attempted when the ticket was submitted
+func (con ArbRetryableTx) WasAutoRedeemed(c ctx, evm mech, ticketId bytes32) (bool, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return false, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return false, err
+	}
+	if retryable == nil {
+		return false, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	return retryable.WasAutoRedeemed()
+}
+
+// IsManualRedeemOnly checks whether retryables to the destination skip the auto-redeem at submission
+func (con ArbRetryableTx) IsManualRedeemOnly(c ctx, evm mech, destination addr) (bool, error) {
+	return c.State.RetryableState().ManualRedeemOnly().IsMember(destination)
+}
+
+// checkNoPendingRedeem protects relayers who've paid to schedule a redeem that hasn't executed yet.
+// Once the owner sets a grace period, a redeem still pending after it no longer holds off deletion.
+func (con ArbRetryableTx) checkNoPendingRedeem(c ctx, evm mech, retryable *retryables.Retryable) error {
+	pending, scheduled, err := retryable.PendingRedeems()
+	if err != nil || pending == 0 {
+		return err
+	}
+	gracePeriod, err := c.State.RetryableState().CancelGracePeriod()
+	if err != nil {
+		return err
+	}
+	if gracePeriod == 0 || evm.Context.Time < arbmath.SaturatingUAdd(scheduled, gracePeriod) {
+		return ErrRedeemPending
+	}
+	return nil
+}
+
+// GetCancelGasEstimate estimates the gas Cancel will consume for the ticket
+func (con ArbRetryableTx) GetCancelGasEstimate(c ctx, evm mech, ticketId bytes32) (uint64, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return 0, err
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return 0, err
+	}
+	if retryable == nil {
+		return 0, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	deletionGas, err := retryable.DeletionGas(c.State.ArbOSVersion())
+	if err != nil {
+		return 0, err
+	}
+	eventCost, err := con.CanceledGasCost(ticketId)
+	if err != nil {
+		return 0, err
+	}
+	// Cancel opens the retryable and reads its beneficiary before deleting it
+	gas := 2*storage.StorageReadCost + deletionGas + eventCost
+	if c.State.ArbOSVersion() >= 31 {
+		// it checks for pending redeems, reading their schedule and the grace period if there are any
+		pending, _, err := retryable.PendingRedeems()
+		if err != nil {
+			return 0, err
+		}
+		gas += storage.StorageReadCost
+		if pending != 0 {
+			gas += 2 * storage.StorageReadCost
+		}
+		// then counts the cancellation
+		gas += storage.StorageReadCost + storage.StorageWriteCost
+	}
+	return gas, nil
+}
+
 // Cancel the ticket and refund its callvalue to its beneficiary
 func (con ArbRetryableTx) Cancel(c ctx, evm mech, ticketId bytes32) error {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return err
+	}
 	if c.txProcessor.CurrentRetryable != nil && ticketId == *c.txProcessor.CurrentRetryable {
 		return ErrSelfModifyingRetryable
 	}
@@ -204,24 +935,200 @@ func (con ArbRetryableTx) Cancel(c ctx, evm mech, ticketId bytes32) error {
 		return err
 	}
 	if retryable == nil {
-		return con.oldNotFoundError(c)
+		return con.notFoundError(c, ticketId, con.oldNotFoundError(c))
 	}
 	beneficiary, err := retryable.Beneficiary()
 	if err != nil {
 		return err
 	}
 	if c.caller != beneficiary {
+		if c.State.ArbOSVersion() >= 31 {
+			return con.NotBeneficiaryError(c.caller)
+		}
 		return errors.New("only the beneficiary may cancel a retryable")
 	}
+	if c.State.ArbOSVersion() >= 31 {
+		if err := con.checkNoPendingRedeem(c, evm, retryable); err != nil {
+			return err
+		}
+	}
 
 	// no refunds are given for deleting retryables because they use rented space
-	_, err = retryableState.DeleteRetryable(ticketId, evm, util.TracingDuringEVM)
+	_, err = retryableState.DeleteRetryable(ticketId, evm, util.TracingDuringEVM, c.State.ArbOSVersion())
 	if err != nil {
 		return err
 	}
+	if c.State.ArbOSVersion() >= 31 {
+		if err := retryableState.IncrementRetryablesCancelled(); err != nil {
+			return err
+		}
+	}
 	return con.Canceled(c, evm, ticketId)
 }
 
+// CancelBatch cancels each ticket like Cancel, reverting the whole batch if the caller can't cancel any of them
+func (con ArbRetryableTx) CancelBatch(c ctx, evm mech, ticketIds []bytes32) error {
+	for _, ticketId := range ticketIds {
+		if err := con.Cancel(c, evm, ticketId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TransferBeneficiary hands a ticket to a new beneficiary (caller must be the current beneficiary)
+func (con ArbRetryableTx) TransferBeneficiary(c ctx, evm mech, ticketId bytes32, newBeneficiary addr) error {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return err
+	}
+	if c.txProcessor.CurrentRetryable != nil && ticketId == *c.txProcessor.CurrentRetryable {
+		return ErrSelfModifyingRetryable
+	}
+	if newBeneficiary == (addr{}) {
+		return errors.New("cannot transfer a retryable to the zero address")
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+	if err != nil {
+		return err
+	}
+	if retryable == nil {
+		return con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+	}
+	beneficiary, err := retryable.Beneficiary()
+	if err != nil {
+		return err
+	}
+	if c.caller != beneficiary {
+		return con.NotBeneficiaryError(c.caller)
+	}
+	if err := retryable.SetBeneficiary(newBeneficiary); err != nil {
+		return err
+	}
+	return con.BeneficiaryTransferred(c, evm, ticketId, beneficiary, newBeneficiary)
+}
+
+// CancelAndFund cancels a ticket, moving its escrowed callvalue into another ticket (caller must be the beneficiary of both)
+func (con ArbRetryableTx) CancelAndFund(c ctx, evm mech, cancelTicketId bytes32, fundTicketId bytes32) error {
+	if err := con.checkTicketId(c, cancelTicketId); err != nil {
+		return err
+	}
+	if err := con.checkTicketId(c, fundTicketId); err != nil {
+		return err
+	}
+	if cancelTicketId == fundTicketId {
+		return errors.New("cannot fund a retryable with itself")
+	}
+	current := c.txProcessor.CurrentRetryable
+	if current != nil && (cancelTicketId == *current || fundTicketId == *current) {
+		return ErrSelfModifyingRetryable
+	}
+	retryableState := c.State.RetryableState()
+	open := func(ticketId bytes32) (*retryables.Retryable, error) {
+		retryable, err := retryableState.OpenRetryable(ticketId, evm.Context.Time)
+		if err != nil {
+			return nil, err
+		}
+		if retryable == nil {
+			return nil, con.notFoundError(c, ticketId, con.NoTicketWithIDError())
+		}
+		beneficiary, err := retryable.Beneficiary()
+		if err != nil {
+			return nil, err
+		}
+		if c.caller != beneficiary {
+			return nil, con.NotBeneficiaryError(c.caller)
+		}
+		return retryable, nil
+	}
+	cancelRetryable, err := open(cancelTicketId)
+	if err != nil {
+		return err
+	}
+	if err := con.checkNoPendingRedeem(c, evm, cancelRetryable); err != nil {
+		return err
+	}
+	fundRetryable, err := open(fundTicketId)
+	if err != nil {
+		return err
+	}
+
+	// move the escrow so that deleting the cancelled ticket has nothing left to refund
+	cancelEscrow := retryables.RetryableEscrowAddress(cancelTicketId)
+	fundEscrow := retryables.RetryableEscrowAddress(fundTicketId)
+	amount := evm.StateDB.GetBalance(cancelEscrow).ToBig()
+	if err := util.TransferBalance(&cancelEscrow, &fundEscrow, amount, evm, util.TracingDuringEVM, "escrow"); err != nil {
+		return err
+	}
+	callvalue, err := fundRetryable.Callvalue()
+	if err != nil {
+		return err
+	}
+	if err := fundRetryable.SetCallvalue(arbmath.BigAdd(callvalue, amount)); err != nil {
+		return err
+	}
+
+	_, err = retryableState.DeleteRetryable(cancelTicketId, evm, util.TracingDuringEVM, c.State.ArbOSVersion())
+	if err != nil {
+		return err
+	}
+	if err := retryableState.IncrementRetryablesCancelled(); err != nil {
+		return err
+	}
+	return con.Canceled(c, evm, cancelTicketId)
+}
+
+// TryDelete deletes a ticket that has fully expired but hasn't been reaped, refunding its escrow to the beneficiary.
+// It returns false if the ticket is already gone, and anyone may call it since live tickets are rejected.
+func (con ArbRetryableTx) TryDelete(c ctx, evm mech, ticketId bytes32) (bool, error) {
+	if err := con.checkTicketId(c, ticketId); err != nil {
+		return false, err
+	}
+	if c.txProcessor.CurrentRetryable != nil && ticketId == *c.txProcessor.CurrentRetryable {
+		return false, ErrSelfModifyingRetryable
+	}
+	retryableState := c.State.RetryableState()
+	retryable, err := retryableState.OpenRetryable(ticketId, 0)
+	if err != nil || retryable == nil {
+		return false, err
+	}
+	// a ticket with windows left will have its timeout extended when reaped
+	windows, err := retryable.TimeoutWindowsLeft()
+	if err != nil {
+		return false, err
+	}
+	timeout, err := retryable.Timeout()
+	if err != nil {
+		return false, err
+	}
+	if windows != 0 || timeout >= evm.Context.Time {
+		return false, errors.New("only expired retryables may be deleted")
+	}
+	deleted, err := retryableState.DeleteRetryable(ticketId, evm, util.TracingDuringEVM, c.State.ArbOSVersion())
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, retryableState.IncrementRetryablesExpired()
+}
+
+// SweepExpired reaps expired retryables from the front of the timeout queue, deleting at most maxToDelete of them.
+// Anyone may call it, paying for the storage it clears.
+func (con ArbRetryableTx) SweepExpired(c ctx, evm mech, maxToDelete uint64) (uint64, error) {
+	if c.txProcessor.CurrentRetryable != nil {
+		// a retry's own ticket is cleaned up when it ends
+		return 0, ErrSelfModifyingRetryable
+	}
+	deleted, err := c.State.RetryableState().SweepExpired(
+		arbmath.SaturatingCast[int](maxToDelete), evm.Context.Time, evm, util.TracingDuringEVM, c.State.ArbOSVersion(),
+	)
+	return uint64(deleted), err
+}
+
+// GetSubmissionNonce gets the number of retryables submitted by the sender, which is aliased for L1 contracts
+func (con ArbRetryableTx) GetSubmissionNonce(c ctx, evm mech, l1Sender addr) (uint64, error) {
+	return c.State.RetryableState().SubmissionCount(l1Sender)
+}
+
 func (con ArbRetryableTx) GetCurrentRedeemer(c ctx, evm mech) (common.Address, error) {
 	if c.txProcessor.CurrentRefundTo != nil {
 		return *c.txProcessor.CurrentRefundTo, nil
@@ -229,6 +1136,14 @@ func (con ArbRetryableTx) GetCurrentRedeemer(c ctx, evm mech) (common.Address, e
 	return common.Address{}, nil
 }
 
+// GetCurrentTicketId gets the id of the retryable being redeemed, or zero outside of a retry
+func (con ArbRetryableTx) GetCurrentTicketId(c ctx, evm mech) (bytes32, error) {
+	if c.txProcessor.CurrentRetryable != nil {
+		return *c.txProcessor.CurrentRetryable, nil
+	}
+	return bytes32{}, nil
+}
+
 func (con ArbRetryableTx) SubmitRetryable(
 	c ctx, evm mech, requestId bytes32, l1BaseFee, deposit, callvalue, gasFeeCap huge,
 	gasLimit uint64, maxSubmissionFee huge,
@@ -4,13 +4,22 @@
 package precompiles
 
 import (
+	"bytes"
+	"errors"
+	"math"
 	"math/big"
 	"testing"
 
+	"github.com/offchainlabs/nitro/arbos/retryables"
 	"github.com/offchainlabs/nitro/arbos/storage"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
 	templates "github.com/offchainlabs/nitro/solgen/go/precompilesgen"
+	"github.com/offchainlabs/nitro/util/arbmath"
 )
 
 func TestRetryableRedeem(t *testing.T) {
@@ -63,3 +72,1493 @@ func TestRetryableRedeem(t *testing.T) {
 		Fail(t, "didn't consume all the expected gas")
 	}
 }
+
+func TestRetryableNumTries(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := evm.Context.Time + 10000000
+	to := common.HexToAddress("0x06070809")
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id,
+		timeout,
+		common.HexToAddress("0x030405"),
+		&to,
+		big.NewInt(0),
+		beneficiary,
+		[]byte{},
+	)
+	Require(t, err)
+
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	retryAddress := common.HexToAddress("6e")
+	call := func(method string, args ...interface{}) []byte {
+		t.Helper()
+		calldata, err := retryABI.Pack(method, args...)
+		Require(t, err)
+		output, _, err := Precompiles()[retryAddress].Call(
+			calldata,
+			retryAddress,
+			retryAddress,
+			common.Address{},
+			big.NewInt(0),
+			false,
+			1000000,
+			evm,
+		)
+		Require(t, err)
+		return output
+	}
+
+	call("redeem", id)
+	call("redeem", id)
+	results, err := retryABI.Unpack("getNumTries", call("getNumTries", id))
+	Require(t, err)
+	if numTries := results[0].(uint64); numTries != 2 {
+		Fail(t, "wrong number of tries", numTries)
+	}
+
+	_, redeems, _, _, err := ArbStatistics{}.GetRetryableStats(precompileCtx, evm)
+	Require(t, err)
+	if redeems != 2 {
+		Fail(t, "wrong number of redeems scheduled", redeems)
+	}
+}
+
+func TestRetryableRedeemTo(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := evm.Context.Time + 10000000
+	to := common.HexToAddress("0x06070809")
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	redeemer := common.HexToAddress("0x0a0b0c0d")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id,
+		timeout,
+		common.HexToAddress("0x030405"),
+		&to,
+		big.NewInt(0),
+		beneficiary,
+		[]byte{},
+	)
+	Require(t, err)
+
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	redeemToCalldata, err := retryABI.Pack("redeemTo", id, redeemer)
+	Require(t, err)
+	retryAddress := common.HexToAddress("6e")
+	redeemTo := func(caller common.Address) error {
+		_, _, err := Precompiles()[retryAddress].Call(
+			redeemToCalldata,
+			retryAddress,
+			retryAddress,
+			caller,
+			big.NewInt(0),
+			false,
+			1000000,
+			evm,
+		)
+		return err
+	}
+
+	if err := redeemTo(redeemer); err == nil {
+		Fail(t, "a stranger redeemed on behalf of another")
+	}
+	Require(t, redeemTo(beneficiary))
+
+	retryContract, err := templates.NewArbRetryableTx(common.Address{}, nil)
+	Require(t, err)
+	//nolint:errcheck
+	logs := evm.StateDB.(*state.StateDB).Logs()
+	scheduled, err := retryContract.ParseRedeemScheduled(*logs[len(logs)-1])
+	Require(t, err)
+	if scheduled.TicketId != id || scheduled.GasDonor != redeemer {
+		Fail(t, "wrong redeem scheduled", scheduled.TicketId, scheduled.GasDonor)
+	}
+
+	// the history records who the redeem was on behalf of, not the beneficiary who scheduled it
+	retryable, err := precompileCtx.State.RetryableState().OpenRetryable(id, evm.Context.Time)
+	Require(t, err)
+	history, _, err := retryable.RedeemHistory()
+	Require(t, err)
+	if len(history) != 1 || history[0] != redeemer {
+		Fail(t, "wrong redeem history", history)
+	}
+
+	if _, err := (ArbRetryableTx{}).RedeemTo(precompileCtx, evm, bytes32{}, redeemer); !errors.Is(err, ErrZeroTicketId) {
+		Fail(t, "redeemed the zero ticket id", err)
+	}
+}
+
+func TestRetryableTimeRemaining(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := evm.Context.Time + 10000
+	to := common.HexToAddress("0x06070809")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id,
+		timeout,
+		common.HexToAddress("0x030405"),
+		&to,
+		big.NewInt(0),
+		common.HexToAddress("0x0301040105090206"),
+		[]byte{},
+	)
+	Require(t, err)
+
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	retryAddress := common.HexToAddress("6e")
+	timeRemaining := func(ticketId common.Hash) (*big.Int, error) {
+		calldata, err := retryABI.Pack("getTimeRemaining", ticketId)
+		Require(t, err)
+		output, _, err := Precompiles()[retryAddress].Call(
+			calldata,
+			retryAddress,
+			retryAddress,
+			common.Address{},
+			big.NewInt(0),
+			false,
+			1000000,
+			evm,
+		)
+		if err != nil {
+			return nil, err
+		}
+		results, err := retryABI.Unpack("getTimeRemaining", output)
+		Require(t, err)
+		return results[0].(*big.Int), nil
+	}
+
+	cases := []struct {
+		now       uint64
+		remaining uint64
+	}{
+		{timeout - 100, 100}, // not yet expired
+		{timeout + 1, 0},     // just expired
+		{timeout + 1000000, 0},
+	}
+	for _, test := range cases {
+		evm.Context.Time = test.now
+		remaining, err := timeRemaining(id)
+		Require(t, err)
+		if !arbmath.BigEquals(remaining, arbmath.UintToBig(test.remaining)) {
+			Fail(t, "wrong time remaining at", test.now, remaining, test.remaining)
+		}
+	}
+
+	if _, err := timeRemaining(common.Hash{}); err == nil {
+		Fail(t, "found a ticket that doesn't exist")
+	}
+}
+
+func TestRetryableExpiredError(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	//nolint:errcheck
+	retryTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx)
+
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := evm.Context.Time + 10000
+	to := common.HexToAddress("0x06070809")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id,
+		timeout,
+		common.HexToAddress("0x030405"),
+		&to,
+		big.NewInt(0),
+		common.HexToAddress("0x0301040105090206"),
+		[]byte{},
+	)
+	Require(t, err)
+
+	evm.Context.Time = timeout + 1
+	_, err = retryTx.GetTimeout(precompileCtx, evm, id)
+	if err == nil || err.Error() != retryTx.TicketExpiredError().Error() {
+		Fail(t, "expected an expired ticket error", err)
+	}
+	_, err = retryTx.GetBeneficiary(precompileCtx, evm, common.Hash{})
+	if err == nil || err.Error() != retryTx.NoTicketWithIDError().Error() {
+		Fail(t, "expected a missing ticket error", err)
+	}
+}
+
+func TestRetryableRedeemWithGasLimit(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := evm.Context.Time + 10000000
+	to := common.HexToAddress("0x06070809")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id,
+		timeout,
+		common.HexToAddress("0x030405"),
+		&to,
+		big.NewInt(0),
+		common.HexToAddress("0x0301040105090206"),
+		[]byte{},
+	)
+	Require(t, err)
+
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	retryAddress := common.HexToAddress("6e")
+	redeem := func(gasLimit uint64) (uint64, error) {
+		calldata, err := retryABI.Pack("redeemWithGasLimit", id, gasLimit)
+		Require(t, err)
+		_, gasLeft, err := Precompiles()[retryAddress].Call(
+			calldata,
+			retryAddress,
+			retryAddress,
+			common.Address{},
+			big.NewInt(0),
+			false,
+			1000000,
+			evm,
+		)
+		return gasLeft, err
+	}
+
+	if _, err := redeem(0); err == nil {
+		Fail(t, "redeemed with no gas")
+	}
+	gasLimit := uint64(100000)
+	gasLeft, err := redeem(gasLimit)
+	Require(t, err)
+	if gasLeft < 1000000-2*gasLimit {
+		Fail(t, "donated more than the gas limit", gasLeft)
+	}
+
+	retryContract, err := templates.NewArbRetryableTx(common.Address{}, nil)
+	Require(t, err)
+	//nolint:errcheck
+	logs := evm.StateDB.(*state.StateDB).Logs()
+	scheduled, err := retryContract.ParseRedeemScheduled(*logs[len(logs)-1])
+	Require(t, err)
+	if scheduled.DonatedGas != gasLimit {
+		Fail(t, "wrong donated gas", scheduled.DonatedGas, gasLimit)
+	}
+}
+
+func TestRetryableSubmissionPrice(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	l1BaseFee, err := precompileCtx.State.L1PricingState().PricePerUnit()
+	Require(t, err)
+
+	for _, dataSize := range []uint64{0, 1, 1024} {
+		base, perByte, err := ArbRetryableTx{}.GetSubmissionPrice(precompileCtx, evm, dataSize)
+		Require(t, err)
+		if !arbmath.BigEquals(base, retryables.RetryableSubmissionFee(0, l1BaseFee)) {
+			Fail(t, "wrong base submission fee", base)
+		}
+		fee := arbmath.BigAdd(base, arbmath.BigMulByUint(perByte, dataSize))
+		expected := retryables.RetryableSubmissionFee(int(dataSize), l1BaseFee)
+		if !arbmath.BigEquals(fee, expected) {
+			Fail(t, "wrong submission fee for", dataSize, fee, expected)
+		}
+		if !arbmath.BigEquals(perByte, arbmath.BigMulByUint(l1BaseFee, 6)) {
+			Fail(t, "wrong per-byte price", perByte)
+		}
+	}
+}
+
+func TestRetryableCancelRevertData(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := evm.Context.Time + 10000000
+	to := common.HexToAddress("0x06070809")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id,
+		timeout,
+		common.HexToAddress("0x030405"),
+		&to,
+		big.NewInt(0),
+		common.HexToAddress("0x0301040105090206"),
+		[]byte{},
+	)
+	Require(t, err)
+
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	cancelCalldata, err := retryABI.Pack("cancel", id)
+	Require(t, err)
+	retryAddress := common.HexToAddress("6e")
+	stranger := common.HexToAddress("0x0a0b0c0d")
+	output, _, err := Precompiles()[retryAddress].Call(
+		cancelCalldata,
+		retryAddress,
+		retryAddress,
+		stranger,
+		big.NewInt(0),
+		false,
+		1000000,
+		evm,
+	)
+	if err == nil {
+		Fail(t, "a stranger canceled the retryable")
+	}
+
+	solErr := retryABI.Errors["NotBeneficiary"]
+	if len(output) < 4 || !bytes.Equal(output[:4], solErr.ID[:4]) {
+		Fail(t, "wrong revert selector", output)
+	}
+	args, err := solErr.Inputs.Unpack(output[4:])
+	Require(t, err)
+	if args[0].(common.Address) != stranger {
+		Fail(t, "wrong revert argument", args[0])
+	}
+}
+
+func TestRetryableData(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := evm.Context.Time + 10000000
+	from := common.HexToAddress("0x030405")
+	to := common.HexToAddress("0x06070809")
+	callvalue := big.NewInt(1000)
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	calldata := make([]byte, 42)
+	for i := range calldata {
+		calldata[i] = byte(i + 3)
+	}
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id,
+		timeout,
+		from,
+		&to,
+		callvalue,
+		beneficiary,
+		calldata,
+	)
+	Require(t, err)
+
+	gotFrom, gotTo, gotCallvalue, gotBeneficiary, gotCalldata, err := ArbRetryableTx{}.GetRetryableData(precompileCtx, evm, id)
+	Require(t, err)
+	if gotFrom != from || gotTo != to || gotBeneficiary != beneficiary {
+		Fail(t, "wrong addresses", gotFrom, gotTo, gotBeneficiary)
+	}
+	if !arbmath.BigEquals(gotCallvalue, callvalue) || !bytes.Equal(gotCalldata, calldata) {
+		Fail(t, "wrong callvalue or calldata", gotCallvalue, gotCalldata)
+	}
+}
+
+func TestRetryableTimeouts(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	retryableState := precompileCtx.State.RetryableState()
+
+	to := common.HexToAddress("0x06070809")
+	create := func(id common.Hash, timeout uint64) {
+		_, err := retryableState.CreateRetryable(id, timeout, to, &to, big.NewInt(0), to, []byte{})
+		Require(t, err)
+	}
+	evm.Context.Time = 1000
+	live := common.BigToHash(big.NewInt(1))
+	expired := common.BigToHash(big.NewInt(2))
+	missing := common.BigToHash(big.NewInt(3))
+	create(live, 5000)
+	create(expired, 500)
+
+	timeouts, err := ArbRetryableTx{}.GetTimeouts(precompileCtx, evm, []bytes32{expired, live, missing, live})
+	Require(t, err)
+	expected := []uint64{0, 5000, 0, 5000}
+	if len(timeouts) != len(expected) {
+		Fail(t, "wrong number of timeouts", len(timeouts))
+	}
+	for i := range expected {
+		if !arbmath.BigEquals(timeouts[i], arbmath.UintToBig(expected[i])) {
+			Fail(t, "wrong timeout", i, timeouts[i], expected[i])
+		}
+	}
+}
+
+func TestRetryableFarFutureTimeout(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	//nolint:errcheck
+	retryTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx)
+
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := uint64(math.MaxUint64 - 10)
+	to := common.HexToAddress("0x06070809")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(id, timeout, to, &to, big.NewInt(0), to, []byte{})
+	Require(t, err)
+
+	got, err := retryTx.GetTimeout(precompileCtx, evm, id)
+	Require(t, err)
+	if got.Sign() <= 0 || !got.IsUint64() || got.Uint64() != timeout {
+		Fail(t, "wrong far future timeout", got)
+	}
+
+	// extending the ticket would wrap around
+	evm.Context.Time = timeout - 1
+	if _, err := retryTx.Keepalive(precompileCtx, evm, id); err == nil {
+		Fail(t, "keepalive overflowed the timeout")
+	}
+}
+
+func TestRetryableRedeemLogsBeforeDonating(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+
+	// the retry would revert, since its destination is a precompile it can't call, but that happens later
+	id := common.BigToHash(big.NewInt(978645611142))
+	timeout := evm.Context.Time + 10000000
+	to := types.ArbRetryableTxAddress
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id, timeout, common.HexToAddress("0x030405"), &to, big.NewInt(0), to, []byte{0xde, 0xad},
+	)
+	Require(t, err)
+
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	redeemCalldata, err := retryABI.Pack("redeem", id)
+	Require(t, err)
+	retryAddress := common.HexToAddress("6e")
+	_, _, err = Precompiles()[retryAddress].Call(
+		redeemCalldata,
+		retryAddress,
+		retryAddress,
+		common.Address{},
+		big.NewInt(0),
+		false,
+		1000000,
+		evm,
+	)
+	Require(t, err)
+
+	retryContract, err := templates.NewArbRetryableTx(common.Address{}, nil)
+	Require(t, err)
+	//nolint:errcheck
+	logs := evm.StateDB.(*state.StateDB).Logs()
+	if len(logs) != 1 {
+		Fail(t, "expected only the RedeemScheduled log", len(logs))
+	}
+	scheduled, err := retryContract.ParseRedeemScheduled(*logs[0])
+	Require(t, err)
+	if scheduled.TicketId != id || scheduled.SequenceNum != 0 || scheduled.DonatedGas == 0 {
+		Fail(t, "wrong redeem scheduled", scheduled.TicketId, scheduled.SequenceNum, scheduled.DonatedGas)
+	}
+}
+
+func TestRetryableSizeBytes(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+
+	id := common.BigToHash(big.NewInt(978645611143))
+	from := common.HexToAddress("0x030405")
+	calldata := make([]byte, 100)
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id, evm.Context.Time+10000000, from, &from, big.NewInt(0), from, calldata,
+	)
+	Require(t, err)
+
+	// six fixed fields, then the calldata's length word and its contents rounded up to whole words
+	expected := 6*32 + 32 + 32*arbmath.WordsForBytes(uint64(len(calldata)))
+	size, err := ArbRetryableTx{}.GetRetryableSizeBytes(precompileCtx, evm, id)
+	Require(t, err)
+	if size != expected || size != 352 {
+		Fail(t, "wrong size", size, expected)
+	}
+}
+
+func TestRetryableCancelDuringPendingRedeem(t *testing.T) {
+	evm := newMockEVMForTesting()
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	precompileCtx := testContext(beneficiary, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableState := precompileCtx.State.RetryableState()
+
+	gracePeriod := uint64(600)
+	Require(t, retryableState.SetCancelGracePeriod(gracePeriod))
+
+	id := common.BigToHash(big.NewInt(978645611144))
+	from := common.HexToAddress("0x030405")
+	retryable, err := retryableState.CreateRetryable(
+		id, evm.Context.Time+10000000, from, &from, big.NewInt(0), beneficiary, []byte{},
+	)
+	Require(t, err)
+	Require(t, retryable.AddPendingRedeem(evm.Context.Time))
+
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+	err = retryableTx.Cancel(precompileCtx, evm, id)
+	if !errors.Is(err, ErrRedeemPending) {
+		Fail(t, "canceled during a pending redeem", err)
+	}
+
+	// without a grace period a pending redeem holds off cancellation indefinitely
+	Require(t, retryableState.SetCancelGracePeriod(0))
+	evm.Context.Time += gracePeriod
+	if err := retryableTx.Cancel(precompileCtx, evm, id); !errors.Is(err, ErrRedeemPending) {
+		Fail(t, "canceled during a pending redeem without a grace period", err)
+	}
+
+	Require(t, retryableState.SetCancelGracePeriod(gracePeriod))
+	Require(t, retryableTx.Cancel(precompileCtx, evm, id))
+	retryable, err = retryableState.OpenRetryable(id, evm.Context.Time)
+	Require(t, err)
+	if retryable != nil {
+		Fail(t, "retryable wasn't canceled")
+	}
+}
+
+func TestRetryableRedeemScheduledGasCost(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	// scheduleRedeem burns the event's cost up front using zero-valued args, which is only
+	// exact while every field is fixed-size, so any signature change must update it too
+	precharged, err := retryableTx.RedeemScheduledGasCost(hash{}, hash{}, 0, 0, addr{}, common.Big0, common.Big0)
+	Require(t, err)
+
+	maxRefund := new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 256), common.Big1)
+	inputs := []struct {
+		nonce, donated    uint64
+		maxRefund, refund *big.Int
+	}{
+		{0, 0, common.Big0, common.Big0},
+		{1, 21000, big.NewInt(1e18), big.NewInt(7)},
+		{math.MaxUint64, math.MaxUint64, maxRefund, maxRefund},
+	}
+	for _, input := range inputs {
+		ticketId := common.BigToHash(big.NewInt(978645611145))
+		retryTxHash := common.BigToHash(big.NewInt(int64(input.donated)))
+		donor := common.HexToAddress("0x0102030405")
+
+		cost, err := retryableTx.RedeemScheduledGasCost(
+			ticketId, retryTxHash, input.nonce, input.donated, donor, input.maxRefund, input.refund,
+		)
+		Require(t, err)
+		gasBefore := precompileCtx.gasLeft
+		Require(t, retryableTx.RedeemScheduled(
+			precompileCtx, evm, ticketId, retryTxHash, input.nonce, input.donated, donor, input.maxRefund, input.refund,
+		))
+		measured := gasBefore - precompileCtx.gasLeft
+		if cost != precharged || measured != precharged {
+			Fail(t, "event cost depends on its args", precharged, cost, measured)
+		}
+	}
+}
+
+func TestRetryableSimulateRedeem(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.HexToAddress("0x0a0b0c0d"), evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableState := precompileCtx.State.RetryableState()
+
+	// with a cap in place both calls donate exactly the cap, so their ids must agree
+	maxRedeemGas := uint64(100000)
+	Require(t, retryableState.SetMaxRedeemGas(maxRedeemGas))
+
+	id := common.BigToHash(big.NewInt(978645611146))
+	from := common.HexToAddress("0x030405")
+	retryable, err := retryableState.CreateRetryable(
+		id, evm.Context.Time+10000000, from, &from, big.NewInt(0), from, []byte{0xde, 0xad},
+	)
+	Require(t, err)
+
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+	simulatedId, donated, err := retryableTx.SimulateRedeem(precompileCtx, evm, id)
+	Require(t, err)
+	if donated != maxRedeemGas {
+		Fail(t, "wrong simulated donation", donated)
+	}
+	numTries, err := retryable.NumTries()
+	Require(t, err)
+	if numTries != 0 {
+		Fail(t, "simulating a redeem changed the ticket", numTries)
+	}
+
+	redeemId, err := retryableTx.Redeem(precompileCtx, evm, id)
+	Require(t, err)
+	if redeemId != simulatedId {
+		Fail(t, "simulated the wrong redeem tx id", simulatedId, redeemId)
+	}
+
+	// a donation the floor rejects must fail in the preview just as it does in the redeem
+	Require(t, retryableState.SetMinRedeemDonation(maxRedeemGas+1))
+	if _, _, err := retryableTx.SimulateRedeem(precompileCtx, evm, id); err == nil {
+		Fail(t, "simulated a redeem below the minimum donation")
+	}
+	if _, err := retryableTx.Redeem(precompileCtx, evm, id); err == nil {
+		Fail(t, "scheduled a redeem below the minimum donation")
+	}
+}
+
+func TestRetryableRedeemChargePerWord(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.HexToAddress("0x0a0b0c0d"), evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableState := precompileCtx.State.RetryableState()
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	// cap the donation so that the two redeems differ only in their per-word charge
+	Require(t, retryableState.SetMaxRedeemGas(100000))
+
+	from := common.HexToAddress("0x030405")
+	calldata := make([]byte, 100)
+	redeemGas := func(seed int64) uint64 {
+		id := common.BigToHash(big.NewInt(seed))
+		_, err := retryableState.CreateRetryable(id, evm.Context.Time+10000000, from, &from, big.NewInt(0), from, calldata)
+		Require(t, err)
+		gasBefore := precompileCtx.gasLeft
+		_, err = retryableTx.Redeem(precompileCtx, evm, id)
+		Require(t, err)
+		return gasBefore - precompileCtx.gasLeft
+	}
+
+	defaultGas := redeemGas(978645611147)
+	charge := uint64(5000)
+	Require(t, retryableState.SetRedeemChargePerWord(charge))
+	configuredGas := redeemGas(978645611148)
+
+	size, err := retryableState.RetryableSizeBytes(common.BigToHash(big.NewInt(978645611148)), evm.Context.Time)
+	Require(t, err)
+	expectedDelta := arbmath.WordsForBytes(size) * (charge - params.SloadGas)
+	if configuredGas-defaultGas != expectedDelta {
+		Fail(t, "wrong redeem charge", defaultGas, configuredGas, expectedDelta)
+	}
+}
+
+func TestRetryableKeepaliveCost(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	id := common.BigToHash(big.NewInt(978645611149))
+	from := common.HexToAddress("0x030405")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id, evm.Context.Time+10000000, from, &from, big.NewInt(0), from, make([]byte, 300),
+	)
+	Require(t, err)
+
+	quote, err := retryableTx.GetKeepaliveCost(precompileCtx, evm, id)
+	Require(t, err)
+
+	// the test context meters storage separately, so the call's gas is just the charges that were quoted
+	gasBefore := precompileCtx.gasLeft
+	_, err = retryableTx.Keepalive(precompileCtx, evm, id)
+	Require(t, err)
+	burned := gasBefore - precompileCtx.gasLeft
+	if !quote.IsUint64() || quote.Uint64() != burned || burned == 0 {
+		Fail(t, "keepalive cost the wrong amount", quote, burned)
+	}
+}
+
+func TestRetryableEscrowedValue(t *testing.T) {
+	evm := newMockEVMForTesting()
+	beneficiary := common.HexToAddress("0x0301040105090207")
+	precompileCtx := testContext(beneficiary, evm)
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	id := common.BigToHash(big.NewInt(978645611150))
+	from := common.HexToAddress("0x030405")
+	callvalue := big.NewInt(1000)
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id, evm.Context.Time+10000000, from, &from, callvalue, beneficiary, []byte{},
+	)
+	Require(t, err)
+	escrow := retryables.RetryableEscrowAddress(id)
+	evm.StateDB.AddBalance(escrow, uint256.MustFromBig(callvalue))
+
+	escrowed, err := retryableTx.GetEscrowedValue(precompileCtx, evm, id)
+	Require(t, err)
+	if !arbmath.BigEquals(escrowed, callvalue) {
+		Fail(t, "wrong escrowed value", escrowed)
+	}
+
+	Require(t, retryableTx.Cancel(precompileCtx, evm, id))
+	if balance := evm.StateDB.GetBalance(beneficiary).ToBig(); !arbmath.BigEquals(balance, callvalue) {
+		Fail(t, "cancel didn't refund the escrow", balance)
+	}
+	if balance := evm.StateDB.GetBalance(escrow); !balance.IsZero() {
+		Fail(t, "escrow wasn't emptied", balance)
+	}
+}
+
+func TestRetryableKeepaliveGasTrace(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	precompileCtx.traceGas = true
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	id := common.BigToHash(big.NewInt(978645611151))
+	from := common.HexToAddress("0x030405")
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id, evm.Context.Time+10000000, from, &from, big.NewInt(0), from, make([]byte, 64),
+	)
+	Require(t, err)
+
+	_, err = retryableTx.Keepalive(precompileCtx, evm, id)
+	Require(t, err)
+
+	total := uint64(0)
+	labels := make(map[string]bool)
+	for _, entry := range precompileCtx.GasTrace() {
+		total += entry.Amount
+		labels[entry.Label] = true
+	}
+	if total != precompileCtx.Burned() || total == 0 {
+		Fail(t, "gas trace doesn't account for the gas burned", total, precompileCtx.Burned())
+	}
+	if !labels["keepalive:update"] || !labels["event:LifetimeExtended"] {
+		Fail(t, "gas trace is missing labels", labels)
+	}
+}
+
+func TestRetryableZeroTicketId(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableTx := ArbRetryableTx{}
+	zero := bytes32{}
+
+	_, redeemErr := retryableTx.Redeem(precompileCtx, evm, zero)
+	_, beneficiaryErr := retryableTx.GetBeneficiary(precompileCtx, evm, zero)
+	_, timeoutErr := retryableTx.GetTimeout(precompileCtx, evm, zero)
+	_, keepaliveErr := retryableTx.Keepalive(precompileCtx, evm, zero)
+	cancelErr := retryableTx.Cancel(precompileCtx, evm, zero)
+	cancelFromErr := retryableTx.CancelAndFund(precompileCtx, evm, zero, common.BigToHash(big.NewInt(1)))
+	fundZeroErr := retryableTx.CancelAndFund(precompileCtx, evm, common.BigToHash(big.NewInt(1)), zero)
+	transferErr := retryableTx.TransferBeneficiary(precompileCtx, evm, zero, common.Address{})
+	_, tryDeleteErr := retryableTx.TryDelete(precompileCtx, evm, zero)
+	_, escrowErr := retryableTx.GetEscrowedValue(precompileCtx, evm, zero)
+	_, initialTimeoutErr := retryableTx.GetInitialTimeout(precompileCtx, evm, zero)
+	_, _, redeemHistoryErr := retryableTx.GetRedeemHistory(precompileCtx, evm, zero)
+	_, _, simulateErr := retryableTx.SimulateRedeem(precompileCtx, evm, zero)
+	errs := map[string]error{
+		"Redeem":                    redeemErr,
+		"GetBeneficiary":            beneficiaryErr,
+		"GetTimeout":                timeoutErr,
+		"Keepalive":                 keepaliveErr,
+		"Cancel":                    cancelErr,
+		"CancelAndFund (cancelled)": cancelFromErr,
+		"CancelAndFund (funded)":    fundZeroErr,
+		"TransferBeneficiary":       transferErr,
+		"TryDelete":                 tryDeleteErr,
+		"GetEscrowedValue":          escrowErr,
+		"GetInitialTimeout":         initialTimeoutErr,
+		"GetRedeemHistory":          redeemHistoryErr,
+		"SimulateRedeem":            simulateErr,
+	}
+	for method, err := range errs {
+		if !errors.Is(err, ErrZeroTicketId) {
+			Fail(t, method, "accepted the zero ticket id", err)
+		}
+	}
+}
+
+func TestRetryableTryDelete(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	retryableState := precompileCtx.State.RetryableState()
+
+	evm.Context.Time = 1000
+	from := common.HexToAddress("0x030405")
+	expired := common.BigToHash(big.NewInt(978645611152))
+	live := common.BigToHash(big.NewInt(978645611153))
+	_, err := retryableState.CreateRetryable(expired, evm.Context.Time-1, from, &from, big.NewInt(0), from, []byte{})
+	Require(t, err)
+	_, err = retryableState.CreateRetryable(live, evm.Context.Time+1, from, &from, big.NewInt(0), from, []byte{})
+	Require(t, err)
+
+	deleted, err := ArbRetryableTx{}.TryDelete(precompileCtx, evm, expired)
+	Require(t, err)
+	if !deleted {
+		Fail(t, "expired retryable wasn't deleted")
+	}
+	retryable, err := retryableState.OpenRetryable(expired, 0)
+	Require(t, err)
+	if retryable != nil {
+		Fail(t, "expired retryable survived")
+	}
+
+	deleted, err = ArbRetryableTx{}.TryDelete(precompileCtx, evm, expired)
+	Require(t, err)
+	if deleted {
+		Fail(t, "deleted a retryable that was already gone")
+	}
+
+	deleted, err = ArbRetryableTx{}.TryDelete(precompileCtx, evm, live)
+	if err == nil || deleted {
+		Fail(t, "deleted a live retryable")
+	}
+}
+
+func TestRetryableMinRedeemDonation(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.HexToAddress("0x0a0b0c0d"), evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableState := precompileCtx.State.RetryableState()
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	from := common.HexToAddress("0x030405")
+	id := common.BigToHash(big.NewInt(978645611154))
+	_, err := retryableState.CreateRetryable(id, evm.Context.Time+10000000, from, &from, big.NewInt(0), from, []byte{})
+	Require(t, err)
+
+	// the redeem's donation is capped below the floor, so it can't be scheduled
+	Require(t, retryableState.SetMaxRedeemGas(50000))
+	Require(t, retryableState.SetMinRedeemDonation(100000))
+	floor, err := ArbGasInfo{}.GetMinRedeemDonation(precompileCtx, evm)
+	Require(t, err)
+	if floor != 100000 {
+		Fail(t, "wrong floor", floor)
+	}
+	if _, err := retryableTx.Redeem(precompileCtx, evm, id); err == nil {
+		Fail(t, "scheduled a redeem below the donation floor")
+	}
+
+	Require(t, retryableState.SetMaxRedeemGas(200000))
+	_, err = retryableTx.Redeem(precompileCtx, evm, id)
+	Require(t, err)
+}
+
+func TestRetryableBeneficiaries(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	retryableState := precompileCtx.State.RetryableState()
+
+	from := common.HexToAddress("0x030405")
+	var ticketIds []bytes32
+	var expected []addr
+	for i := int64(0); i < 5; i++ {
+		id := common.BigToHash(big.NewInt(978645611160 + i))
+		ticketIds = append(ticketIds, id)
+		if i%2 == 1 {
+			// leave every other ticket missing
+			expected = append(expected, addr{})
+			continue
+		}
+		beneficiary := common.BigToAddress(big.NewInt(0x0301 + i))
+		_, err := retryableState.CreateRetryable(id, evm.Context.Time+10000000, from, &from, big.NewInt(0), beneficiary, []byte{})
+		Require(t, err)
+		expected = append(expected, beneficiary)
+	}
+
+	beneficiaries, err := ArbRetryableTx{}.GetBeneficiaries(precompileCtx, evm, ticketIds)
+	Require(t, err)
+	if len(beneficiaries) != len(expected) {
+		Fail(t, "wrong number of beneficiaries", len(beneficiaries))
+	}
+	for i := range expected {
+		if beneficiaries[i] != expected[i] {
+			Fail(t, "wrong beneficiary", i, beneficiaries[i], expected[i])
+		}
+	}
+}
+
+func TestRetryableBeneficiaryGasIgnoresSize(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	retryableState := precompileCtx.State.RetryableState()
+	from := common.HexToAddress("0x030405")
+
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	retryAddress := common.HexToAddress("6e")
+	gasUsed := func(calldataSize int) uint64 {
+		t.Helper()
+		id := common.BigToHash(big.NewInt(978645611180 + int64(calldataSize)))
+		_, err := retryableState.CreateRetryable(
+			id, evm.Context.Time+10000000, from, &from, big.NewInt(0), from, make([]byte, calldataSize),
+		)
+		Require(t, err)
+		getBeneficiaryCalldata, err := retryABI.Pack("getBeneficiary", id)
+		Require(t, err)
+		_, gasLeft, err := Precompiles()[retryAddress].Call(
+			getBeneficiaryCalldata, retryAddress, retryAddress, from, big.NewInt(0), true, 1000000, evm,
+		)
+		Require(t, err)
+		return 1000000 - gasLeft
+	}
+
+	// only the timeout and beneficiary words are read, however large the ticket
+	small := gasUsed(0)
+	large := gasUsed(10000)
+	if small != large {
+		Fail(t, "GetBeneficiary's charge depends on the ticket's size", small, large)
+	}
+	if small < 2*storage.StorageReadCost {
+		Fail(t, "GetBeneficiary didn't pay for its reads", small)
+	}
+}
+
+func TestRetryableCannotModifyItself(t *testing.T) {
+	evm := newMockEVMForTesting()
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	precompileCtx := testContext(beneficiary, evm)
+	id := common.BigToHash(big.NewInt(978645611190))
+	_, err := precompileCtx.State.RetryableState().CreateRetryable(
+		id, evm.Context.Time+10000000, beneficiary, &beneficiary, big.NewInt(0), beneficiary, []byte{},
+	)
+	Require(t, err)
+
+	// simulate the ticket's own retry calling back into the precompile
+	precompileCtx.txProcessor.CurrentRetryable = &id
+	defer func() { precompileCtx.txProcessor.CurrentRetryable = nil }()
+
+	retryableTx := ArbRetryableTx{}
+	if _, err := retryableTx.Redeem(precompileCtx, evm, id); !errors.Is(err, ErrSelfModifyingRetryable) {
+		Fail(t, "a retry redeemed its own ticket", err)
+	}
+	if err := retryableTx.Cancel(precompileCtx, evm, id); !errors.Is(err, ErrSelfModifyingRetryable) {
+		Fail(t, "a retry cancelled its own ticket", err)
+	}
+	retryable, err := precompileCtx.State.RetryableState().OpenRetryable(id, evm.Context.Time)
+	Require(t, err)
+	if retryable == nil {
+		Fail(t, "the ticket was deleted by its own retry")
+	}
+}
+
+func TestRetryableCancelBatch(t *testing.T) {
+	evm := newMockEVMForTesting()
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	precompileCtx := testContext(beneficiary, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableState := precompileCtx.State.RetryableState()
+	//nolint:errcheck
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx)
+	//nolint:errcheck
+	statedb := evm.StateDB.(*state.StateDB)
+
+	createTickets := func(base int64, owners ...addr) []bytes32 {
+		t.Helper()
+		ids := make([]bytes32, len(owners))
+		for i, owner := range owners {
+			ids[i] = common.BigToHash(big.NewInt(base + int64(i)))
+			_, err := retryableState.CreateRetryable(
+				ids[i], evm.Context.Time+10000000, owner, &owner, big.NewInt(0), owner, []byte{},
+			)
+			Require(t, err)
+		}
+		return ids
+	}
+	exists := func(id bytes32) bool {
+		t.Helper()
+		retryable, err := retryableState.OpenRetryable(id, evm.Context.Time)
+		Require(t, err)
+		return retryable != nil
+	}
+
+	owned := createTickets(978645611200, beneficiary, beneficiary, beneficiary)
+	logs := len(statedb.Logs())
+	Require(t, retryableTx.CancelBatch(precompileCtx, evm, owned))
+	for _, id := range owned {
+		if exists(id) {
+			Fail(t, "ticket wasn't cancelled", id)
+		}
+	}
+	if len(statedb.Logs()) != logs+len(owned) {
+		Fail(t, "expected a Canceled event per ticket", len(statedb.Logs())-logs)
+	}
+
+	// the evm reverts a failed precompile call to its snapshot, undoing the tickets cancelled before the failure
+	stranger := common.HexToAddress("0x0a0b0c0d")
+	mixed := createTickets(978645611210, beneficiary, stranger, beneficiary)
+	snapshot := statedb.Snapshot()
+	if err := retryableTx.CancelBatch(precompileCtx, evm, mixed); err == nil {
+		Fail(t, "cancelled another beneficiary's ticket")
+	}
+	statedb.RevertToSnapshot(snapshot)
+	for _, id := range mixed {
+		if !exists(id) {
+			Fail(t, "a failed batch cancelled a ticket", id)
+		}
+	}
+}
+
+func TestRetryableStats(t *testing.T) {
+	evm := newMockEVMForTesting()
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	precompileCtx := testContext(beneficiary, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableState := precompileCtx.State.RetryableState()
+	Require(t, retryableState.SetMaxRedeemGas(100000))
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	stats := func() [4]uint64 {
+		t.Helper()
+		created, redeems, cancelled, expired, err := ArbStatistics{}.GetRetryableStats(precompileCtx, evm)
+		Require(t, err)
+		return [4]uint64{created, redeems, cancelled, expired}
+	}
+	create := func(id bytes32, timeout uint64) {
+		t.Helper()
+		_, err := retryableState.CreateRetryable(id, timeout, beneficiary, &beneficiary, big.NewInt(0), beneficiary, []byte{})
+		Require(t, err)
+	}
+
+	// the expired ticket goes first so that it's at the front of the timeout queue
+	expiring := common.BigToHash(big.NewInt(978645611220))
+	redeemed := common.BigToHash(big.NewInt(978645611221))
+	cancelled := common.BigToHash(big.NewInt(978645611222))
+	create(expiring, evm.Context.Time+1)
+	create(redeemed, evm.Context.Time+10000000)
+	create(cancelled, evm.Context.Time+10000000)
+
+	before := stats()
+	// the submission path counts each new ticket, since CreateRetryable is also used to import them
+	Require(t, retryableState.IncrementRetryablesCreated())
+	_, err := retryableTx.Redeem(precompileCtx, evm, redeemed)
+	Require(t, err)
+	Require(t, retryableTx.Cancel(precompileCtx, evm, cancelled))
+	evm.Context.Time += 2
+	swept, err := retryableTx.SweepExpired(precompileCtx, evm, 10)
+	Require(t, err)
+	if swept != 1 {
+		Fail(t, "wrong number of tickets swept", swept)
+	}
+
+	after := stats()
+	for i := range after {
+		if after[i] != before[i]+1 {
+			Fail(t, "a counter didn't advance by exactly one", i, before, after)
+		}
+	}
+}
+
+func TestRetryableMaxTriesCancel(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.HexToAddress("0x0a0b0c0d"), evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableState := precompileCtx.State.RetryableState()
+	Require(t, retryableState.SetMaxRedeemGas(100000))
+	Require(t, retryableState.SetMaxTries(1))
+	Require(t, retryableState.SetMaxTriesPolicy(retryables.MaxTriesCancel))
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	id := common.BigToHash(big.NewInt(978645611240))
+	from := common.HexToAddress("0x030405")
+	_, err := retryableState.CreateRetryable(id, evm.Context.Time+10000000, from, &from, big.NewInt(0), from, []byte{})
+	Require(t, err)
+	_, err = retryableTx.Redeem(precompileCtx, evm, id)
+	Require(t, err)
+
+	cancelledBefore, err := retryableState.RetryablesCancelled()
+	Require(t, err)
+	redeemId, err := retryableTx.Redeem(precompileCtx, evm, id)
+	Require(t, err)
+	if redeemId != (bytes32{}) {
+		Fail(t, "scheduled a redeem past the maximum number of tries", redeemId)
+	}
+	retryable, err := retryableState.OpenRetryable(id, evm.Context.Time)
+	Require(t, err)
+	if retryable != nil {
+		Fail(t, "exhausted retryable wasn't cancelled")
+	}
+	cancelledAfter, err := retryableState.RetryablesCancelled()
+	Require(t, err)
+	if cancelledAfter != cancelledBefore+1 {
+		Fail(t, "cancelling an exhausted retryable wasn't counted", cancelledBefore, cancelledAfter)
+	}
+}
+
+func TestRetryableBatchRedeemOverhead(t *testing.T) {
+	evm := newMockEVMForTesting()
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	precompileCtx := testContext(beneficiary, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	// keep every write nonzero, as the estimate assumes
+	evm.Context.Time = 1000
+	Require(t, precompileCtx.State.L2PricingState().SetGasBacklog(1<<40))
+	retryableState := precompileCtx.State.RetryableState()
+	Require(t, retryableState.SetMaxRedeemGas(100000))
+	Require(t, retryableState.SetMaxTries(10))
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	retryContract, err := templates.NewArbRetryableTx(common.Address{}, nil)
+	Require(t, err)
+	retryAddress := types.ArbRetryableTxAddress
+	statedb := evm.StateDB.(*state.StateDB) //nolint:errcheck
+
+	nextId := int64(978645611230)
+	create := func(n int) []bytes32 {
+		t.Helper()
+		ids := make([]bytes32, n)
+		for i := range ids {
+			ids[i] = common.BigToHash(big.NewInt(nextId))
+			nextId++
+			calldata := make([]byte, 70)
+			_, err := retryableState.CreateRetryable(
+				ids[i], evm.Context.Time+10000000, beneficiary, &beneficiary, big.NewInt(0), beneficiary, calldata,
+			)
+			Require(t, err)
+		}
+		return ids
+	}
+
+	// gasBurned gets the gas ProcessTickets burns beyond what it donates to the scheduled retries
+	gasBurned := func(ids []bytes32) uint64 {
+		t.Helper()
+		calldata, err := retryABI.Pack("processTickets", ids, []bytes32{})
+		Require(t, err)
+		logCount := len(statedb.Logs())
+		supplied := uint64(10000000)
+		_, gasLeft, err := Precompiles()[retryAddress].Call(
+			calldata, retryAddress, retryAddress, beneficiary, big.NewInt(0), false, supplied, evm,
+		)
+		Require(t, err)
+		logs := statedb.Logs()[logCount:]
+		if len(logs) != len(ids) {
+			Fail(t, "wrong number of redeems scheduled", len(logs), len(ids))
+		}
+		donated := uint64(0)
+		for _, log := range logs {
+			scheduled, err := retryContract.ParseRedeemScheduled(*log)
+			Require(t, err)
+			donated += scheduled.DonatedGas
+		}
+		return supplied - gasLeft - donated
+	}
+
+	// the call itself costs a fixed amount to open the state, which the estimate leaves out
+	baseEstimate, err := retryableTx.EstimateBatchRedeemOverhead(precompileCtx, evm, nil)
+	Require(t, err)
+	baseBurned := gasBurned(nil)
+	for _, n := range []int{1, 3} {
+		ids := create(n)
+		estimate, err := retryableTx.EstimateBatchRedeemOverhead(precompileCtx, evm, ids)
+		Require(t, err)
+		burned := gasBurned(ids)
+		if estimate-baseEstimate != burned-baseBurned {
+			Fail(t, "wrong overhead estimate", n, estimate-baseEstimate, burned-baseBurned)
+		}
+	}
+}
+
+func TestRetryableCancelAndFund(t *testing.T) {
+	evm := newMockEVMForTesting()
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	stranger := common.HexToAddress("0x0a0b0c0d")
+	precompileCtx := testContext(beneficiary, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	retryableState := precompileCtx.State.RetryableState()
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+
+	create := func(id bytes32, owner common.Address, callvalue *big.Int) {
+		t.Helper()
+		_, err := retryableState.CreateRetryable(
+			id, evm.Context.Time+10000000, owner, &owner, callvalue, owner, []byte{},
+		)
+		Require(t, err)
+		evm.StateDB.AddBalance(retryables.RetryableEscrowAddress(id), uint256.MustFromBig(callvalue))
+	}
+	exists := func(id bytes32) bool {
+		t.Helper()
+		retryable, err := retryableState.OpenRetryable(id, evm.Context.Time)
+		Require(t, err)
+		return retryable != nil
+	}
+
+	cancelled := common.BigToHash(big.NewInt(978645611240))
+	funded := common.BigToHash(big.NewInt(978645611241))
+	foreign := common.BigToHash(big.NewInt(978645611242))
+	missing := common.BigToHash(big.NewInt(978645611243))
+	create(cancelled, beneficiary, big.NewInt(300))
+	create(funded, beneficiary, big.NewInt(700))
+	create(foreign, stranger, big.NewInt(500))
+
+	// each ticket must belong to the caller
+	if err := retryableTx.CancelAndFund(precompileCtx, evm, foreign, funded); err == nil {
+		Fail(t, "cancelled a ticket belonging to someone else")
+	}
+	if err := retryableTx.CancelAndFund(precompileCtx, evm, cancelled, foreign); err == nil {
+		Fail(t, "funded a ticket belonging to someone else")
+	}
+	strangerCtx := testContext(stranger, evm)
+	if err := retryableTx.CancelAndFund(strangerCtx, evm, cancelled, funded); err == nil {
+		Fail(t, "a stranger moved another's escrow")
+	}
+	if err := retryableTx.CancelAndFund(precompileCtx, evm, cancelled, cancelled); err == nil {
+		Fail(t, "funded a ticket with itself")
+	}
+	if err := retryableTx.CancelAndFund(precompileCtx, evm, missing, funded); err == nil {
+		Fail(t, "cancelled a missing ticket")
+	}
+	if err := retryableTx.CancelAndFund(precompileCtx, evm, cancelled, missing); err == nil {
+		Fail(t, "funded a missing ticket")
+	}
+	if !exists(cancelled) || !exists(funded) || !exists(foreign) {
+		Fail(t, "a rejected move deleted a ticket")
+	}
+
+	Require(t, retryableTx.CancelAndFund(precompileCtx, evm, cancelled, funded))
+	if exists(cancelled) {
+		Fail(t, "the cancelled ticket still exists")
+	}
+	cancelledEscrow := retryables.RetryableEscrowAddress(cancelled)
+	if balance := evm.StateDB.GetBalance(cancelledEscrow); !balance.IsZero() {
+		Fail(t, "the cancelled ticket's escrow wasn't emptied", balance)
+	}
+	fundedEscrow := retryables.RetryableEscrowAddress(funded)
+	if balance := evm.StateDB.GetBalance(fundedEscrow).ToBig(); !arbmath.BigEquals(balance, big.NewInt(1000)) {
+		Fail(t, "the funded ticket's escrow wasn't credited", balance)
+	}
+	if balance := evm.StateDB.GetBalance(beneficiary); !balance.IsZero() {
+		Fail(t, "the escrow was refunded instead of moved", balance)
+	}
+	fundRetryable, err := retryableState.OpenRetryable(funded, evm.Context.Time)
+	Require(t, err)
+	callvalue, err := fundRetryable.Callvalue()
+	Require(t, err)
+	if !arbmath.BigEquals(callvalue, big.NewInt(1000)) {
+		Fail(t, "the funded ticket's callvalue wasn't raised", callvalue)
+	}
+	if balance := evm.StateDB.GetBalance(retryables.RetryableEscrowAddress(foreign)).ToBig(); !arbmath.BigEquals(balance, big.NewInt(500)) {
+		Fail(t, "an uninvolved ticket's escrow changed", balance)
+	}
+}
+
+func TestRetryableCancelGasEstimate(t *testing.T) {
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	retryAddress := types.ArbRetryableTxAddress
+
+	tests := []struct {
+		name    string
+		version uint64
+		history bool
+		pending bool
+	}{
+		{"before ArbOS 31", 30, false, false},
+		{"plain", 31, false, false},
+		{"histories", 31, true, false},
+		{"elapsed redeem", 31, false, true},
+		{"everything", 31, true, true},
+	}
+	for _, test := range tests {
+		evm := newMockEVMForTestingAtArbOSVersion(test.version)
+		evm.Context.Time = 1000
+		precompileCtx := testContext(beneficiary, evm)
+		if precompileCtx.State.ArbOSVersion() != test.version {
+			Fail(t, test.name, "wrong ArbOS version", precompileCtx.State.ArbOSVersion())
+		}
+		retryableState := precompileCtx.State.RetryableState()
+
+		// the other ticket keeps the storage total nonzero, as the estimate assumes
+		id := common.BigToHash(big.NewInt(978645611250))
+		other := common.BigToHash(big.NewInt(978645611251))
+		for _, ticketId := range []bytes32{other, id} {
+			created, err := retryableState.CreateRetryable(
+				ticketId, evm.Context.Time+10000000, beneficiary, &beneficiary, big.NewInt(0), beneficiary, make([]byte, 70),
+			)
+			Require(t, err)
+			Require(t, retryableState.TrackStorage(created))
+		}
+		retryable, err := retryableState.OpenRetryable(id, evm.Context.Time)
+		Require(t, err)
+		if test.history {
+			Require(t, retryable.RecordRedeem(beneficiary, 0))
+			Require(t, retryable.RecordRedeem(beneficiary, 1))
+			Require(t, retryable.SetBeneficiary(beneficiary))
+		}
+		if test.pending {
+			Require(t, retryableState.SetCancelGracePeriod(10))
+			Require(t, retryable.AddPendingRedeem(evm.Context.Time))
+			evm.Context.Time += 10
+		}
+
+		estimate, err := retryableTx.GetCancelGasEstimate(precompileCtx, evm, id)
+		Require(t, err)
+		calldata, err := retryABI.Pack("cancel", id)
+		Require(t, err)
+		supplied := uint64(1000000)
+		_, gasLeft, err := Precompiles()[retryAddress].Call(
+			calldata, retryAddress, retryAddress, beneficiary, big.NewInt(0), false, supplied, evm,
+		)
+		Require(t, err, test.name)
+
+		// the call also copies in the id and opens the state, which the estimate leaves out
+		burned := supplied - gasLeft - params.CopyGas - storage.StorageReadCost
+		if burned != estimate {
+			Fail(t, test.name, "wrong cancel gas estimate", estimate, burned)
+		}
+		retryable, err = retryableState.OpenRetryable(id, evm.Context.Time)
+		Require(t, err)
+		if retryable != nil {
+			Fail(t, test.name, "the ticket wasn't cancelled")
+		}
+	}
+}
+
+func TestRetryableProcessTickets(t *testing.T) {
+	evm := newMockEVMForTesting()
+	beneficiary := common.HexToAddress("0x0301040105090206")
+	precompileCtx := testContext(beneficiary, evm)
+	if precompileCtx.State.ArbOSVersion() < 31 {
+		Require(t, precompileCtx.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+	evm.Context.Time = 1000
+	evm.Context.BaseFee = big.NewInt(100000000)
+	retryableState := precompileCtx.State.RetryableState()
+	Require(t, retryableState.SetMaxRedeemGas(0))
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx) //nolint:errcheck
+	retryContract, err := templates.NewArbRetryableTx(common.Address{}, nil)
+	Require(t, err)
+	statedb := evm.StateDB.(*state.StateDB) //nolint:errcheck
+
+	// tickets close to expiring, so that they can be kept alive
+	nextId := int64(978645611260)
+	create := func(n int) []bytes32 {
+		t.Helper()
+		ids := make([]bytes32, n)
+		for i := range ids {
+			ids[i] = common.BigToHash(big.NewInt(nextId))
+			nextId++
+			_, err := retryableState.CreateRetryable(
+				ids[i], evm.Context.Time+100, beneficiary, &beneficiary, big.NewInt(0), beneficiary, make([]byte, 70),
+			)
+			Require(t, err)
+		}
+		return ids
+	}
+	redeemIds := create(3)
+	keepaliveIds := create(2)
+
+	// the test context meters storage separately, so only the explicit charges come out of the call's gas
+	gasLeft := uint64(2000000)
+	for _, id := range keepaliveIds {
+		quote, err := retryableTx.GetKeepaliveCost(precompileCtx, evm, id)
+		Require(t, err)
+		gasLeft -= quote.Uint64()
+	}
+	size, err := retryableState.RetryableSizeBytes(redeemIds[0], evm.Context.Time)
+	Require(t, err)
+	chargePerWord, err := retryableState.RedeemChargePerWord(precompileCtx.State.ArbOSVersion())
+	Require(t, err)
+	sizeCharge := chargePerWord * arbmath.WordsForBytes(size)
+	eventCost, err := retryableTx.RedeemScheduledGasCost(hash{}, hash{}, 0, 0, addr{}, common.Big0, common.Big0)
+	Require(t, err)
+	returnCost := params.CopyGas * uint64(4+len(redeemIds)+len(keepaliveIds))
+	futureGasCosts := eventCost + params.CopyGas + storage.StorageReadCost + storage.StorageWriteCost + returnCost
+	expectedDonations := make([]uint64, len(redeemIds))
+	for i := range redeemIds {
+		share := (gasLeft - returnCost) / uint64(len(redeemIds)-i)
+		expectedDonations[i] = arbmath.MinInt(gasLeft-sizeCharge-futureGasCosts, share)
+		gasLeft -= sizeCharge + eventCost + expectedDonations[i]
+	}
+
+	lifetime, err := retryableState.Lifetime(precompileCtx.State.ArbOSVersion())
+	Require(t, err)
+	precompileCtx.gasLeft = 2000000
+	logCount := len(statedb.Logs())
+	redeemTxIds, timeouts, err := retryableTx.ProcessTickets(precompileCtx, evm, redeemIds, keepaliveIds)
+	Require(t, err)
+	if precompileCtx.gasLeft != gasLeft || gasLeft < returnCost {
+		Fail(t, "wrong gas left to return the results", precompileCtx.gasLeft, gasLeft, returnCost)
+	}
+
+	if len(timeouts) != len(keepaliveIds) {
+		Fail(t, "wrong number of timeouts", len(timeouts))
+	}
+	for i, id := range keepaliveIds {
+		timeout, err := retryableTx.GetTimeout(precompileCtx, evm, id)
+		Require(t, err)
+		expected := arbmath.UintToBig(evm.Context.Time + 100 + lifetime)
+		if !arbmath.BigEquals(timeouts[i], expected) || !arbmath.BigEquals(timeout, expected) {
+			Fail(t, "wrong timeout for keepalive", i, timeouts[i], timeout, expected)
+		}
+	}
+
+	var scheduled []*templates.ArbRetryableTxRedeemScheduled
+	for _, log := range statedb.Logs()[logCount:] {
+		if event, err := retryContract.ParseRedeemScheduled(*log); err == nil {
+			scheduled = append(scheduled, event)
+		}
+	}
+	if len(redeemTxIds) != len(redeemIds) || len(scheduled) != len(redeemIds) {
+		Fail(t, "wrong number of redeems scheduled", len(redeemTxIds), len(scheduled))
+	}
+	for i, event := range scheduled {
+		if event.TicketId != redeemIds[i] || event.RetryTxHash != redeemTxIds[i] {
+			Fail(t, "redeem scheduled out of order", i)
+		}
+		if event.DonatedGas != expectedDonations[i] {
+			Fail(t, "wrong donation for redeem", i, event.DonatedGas, expectedDonations[i])
+		}
+	}
+
+	// through the precompile, with storage metered, there's still enough gas left to return both arrays
+	retryABI, err := templates.ArbRetryableTxMetaData.GetAbi()
+	Require(t, err)
+	calldata, err := retryABI.Pack("processTickets", create(3), create(2))
+	Require(t, err)
+	retryAddress := types.ArbRetryableTxAddress
+	output, _, err := Precompiles()[retryAddress].Call(
+		calldata, retryAddress, retryAddress, beneficiary, big.NewInt(0), false, 2000000, evm,
+	)
+	Require(t, err)
+	results, err := retryABI.Unpack("processTickets", output)
+	Require(t, err)
+	if len(results) != 2 {
+		Fail(t, "wrong number of results", len(results))
+	}
+	if ids, ok := results[0].([][32]byte); !ok || len(ids) != 3 {
+		Fail(t, "wrong redeem tx ids returned", results[0])
+	}
+	if returned, ok := results[1].([]*big.Int); !ok || len(returned) != 2 {
+		Fail(t, "wrong timeouts returned", results[1])
+	}
+}
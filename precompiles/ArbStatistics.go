@@ -24,3 +24,23 @@ func (con ArbStatistics) GetStats(c ctx, evm mech) (huge, huge, huge, huge, huge
 	classicNumContracts := big.NewInt(0) // TODO: hardcode the final value from Arbitrum Classic
 	return blockNum, classicNumAccounts, classicStorageSum, classicGasSum, classicNumTxes, classicNumContracts, nil
 }
+
+// GetRetryableStats returns the number of retryables created, redeems scheduled, retryables cancelled, and
+// expired retryables deleted since ArbOS 31
+func (con ArbStatistics) GetRetryableStats(c ctx, evm mech) (uint64, uint64, uint64, uint64, error) {
+	retryableState := c.State.RetryableState()
+	created, err := retryableState.RetryablesCreated()
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	redeems, err := retryableState.RedeemsScheduled()
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	cancelled, err := retryableState.RetryablesCancelled()
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	expired, err := retryableState.RetryablesExpired()
+	return created, redeems, cancelled, expired, err
+}
@@ -14,6 +14,8 @@ import (
 	"github.com/offchainlabs/nitro/util/merkletree"
 )
 
+var ErrZeroWithdrawal = errors.New("cannot withdraw zero eth")
+
 // ArbSys provides system-level functionality for interacting with L1 and understanding the call stack.
 type ArbSys struct {
 	Address                 addr // 0x64
@@ -65,6 +67,15 @@ func (con *ArbSys) ArbOSVersion(c ctx, evm mech) (huge, error) {
 	return version, nil
 }
 
+// GetSupportedSelectors returns the method selectors a precompile implements at the current ArbOS version
+func (con *ArbSys) GetSupportedSelectors(c ctx, evm mech, precompile addr) ([]bytes4, error) {
+	table, ok := dispatchTable[precompile]
+	if !ok {
+		return []bytes4{}, nil
+	}
+	return table.SupportedSelectors(c.State.ArbOSVersion()), nil
+}
+
 // GetStorageGasAvailable returns 0 since Nitro has no concept of storage gas
 func (con *ArbSys) GetStorageGasAvailable(c ctx, evm mech) (huge, error) {
 	return big.NewInt(0), nil
@@ -202,8 +213,17 @@ func (con ArbSys) SendMerkleTreeState(c ctx, evm mech) (huge, bytes32, []bytes32
 	return big.NewInt(int64(size)), rootHash, partials, nil
 }
 
+// GetL2ToL1TxCount gets the number of L2 to L1 messages sent so far, which is also the next message's index
+func (con ArbSys) GetL2ToL1TxCount(c ctx, evm mech) (huge, error) {
+	size, err := c.State.SendMerkleAccumulator().Size()
+	return arbmath.UintToBig(size), err
+}
+
 // WithdrawEth send paid eth to the destination on L1
 func (con ArbSys) WithdrawEth(c ctx, evm mech, value huge, destination addr) (huge, error) {
+	if c.State.ArbOSVersion() >= 31 && value.Sign() == 0 {
+		return nil, ErrZeroWithdrawal
+	}
 	return con.SendTxToL1(c, evm, value, destination, []byte{})
 }
 
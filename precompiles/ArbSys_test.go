@@ -0,0 +1,84 @@
+// Copyright 2024, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package precompiles
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+
+	"github.com/offchainlabs/nitro/arbos/util"
+)
+
+func TestWithdrawEth(t *testing.T) {
+	evm := newMockEVMForTestingWithVersionAndRunMode(nil, core.MessageCommitMode)
+	//nolint:errcheck
+	sys := Precompiles()[types.ArbSysAddress].Precompile().implementer.Interface().(*ArbSys)
+	caller := common.BytesToAddress([]byte{1, 2, 3})
+	destination := common.BytesToAddress([]byte{4, 5, 6})
+	context := testContext(caller, evm)
+	if context.State.ArbOSVersion() < 31 {
+		Require(t, context.State.UpgradeArbosVersion(31, false, evm.StateDB, evm.ChainConfig()))
+	}
+
+	for i := int64(0); i < 2; i++ {
+		value := big.NewInt(1000 + i)
+
+		// the evm deposits a payable call's value to the precompile before running it
+		evm.StateDB.AddBalance(types.ArbSysAddress, uint256.MustFromBig(value))
+		logs := len(evm.StateDB.(*state.StateDB).Logs())
+
+		index, err := sys.WithdrawEth(context, evm, value, destination)
+		Require(t, err)
+		if index.Int64() != i {
+			Fail(t, "wrong message index", index, i)
+		}
+		count, err := sys.GetL2ToL1TxCount(context, evm)
+		Require(t, err)
+		if count.Int64() != i+1 {
+			Fail(t, "wrong message count", count)
+		}
+		if !evm.StateDB.GetBalance(types.ArbSysAddress).IsZero() {
+			Fail(t, "withdrawn value wasn't burnt")
+		}
+		if len(evm.StateDB.(*state.StateDB).Logs()) == logs {
+			Fail(t, "no event emitted for the withdrawal")
+		}
+	}
+
+	_, err := sys.WithdrawEth(context, evm, big.NewInt(0), destination)
+	if !errors.Is(err, ErrZeroWithdrawal) {
+		Fail(t, "zero-value withdrawal should be rejected", err)
+	}
+}
+
+func TestL1AddressAliasing(t *testing.T) {
+	evm := newMockEVMForTesting()
+	context := testContext(common.Address{}, evm)
+	sys := &ArbSys{}
+
+	cases := map[common.Address]common.Address{
+		common.HexToAddress("0x0000000000000000000000000000000000000000"): common.HexToAddress("0x1111000000000000000000000000000000001111"),
+		common.HexToAddress("0x0123456789abcdef0123456789abcdef01234567"): common.HexToAddress("0x1234456789abcdef0123456789abcdef01235678"),
+		// the offset wraps around the top of the address space
+		common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff"): common.HexToAddress("0x1111000000000000000000000000000000001110"),
+		common.HexToAddress("0xeeeeffffffffffffffffffffffffffffffffeeef"): common.HexToAddress("0x0000000000000000000000000000000000000000"),
+	}
+	for sender, expected := range cases {
+		alias, err := sys.MapL1SenderContractAddressToL2Alias(context, sender, common.Address{})
+		Require(t, err)
+		if alias != expected {
+			Fail(t, "wrong alias", sender, alias, expected)
+		}
+		if unaliased := util.InverseRemapL1Address(alias); unaliased != sender {
+			Fail(t, "aliasing didn't round trip", sender, unaliased)
+		}
+	}
+}
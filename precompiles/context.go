@@ -33,13 +33,29 @@ type Context struct {
 	State       *arbosState.ArbosState
 	tracingInfo *util.TracingInfo
 	readOnly    bool
+	traceGas    bool
+	gasTrace    []GasChargeEntry
+}
+
+// GasChargeEntry records one charge against a precompile call's gas, when gas tracing is enabled
+type GasChargeEntry struct {
+	Label  string
+	Amount uint64
 }
 
 func (c *Context) Burn(amount uint64) error {
+	return c.burnLabeled("unlabeled", amount)
+}
+
+// burnLabeled burns like Burn, recording the charge under the label if gas tracing is enabled
+func (c *Context) burnLabeled(label string, amount uint64) error {
 	if c.gasLeft < amount {
 		return c.BurnOut()
 	}
 	c.gasLeft -= amount
+	if c.traceGas {
+		c.gasTrace = append(c.gasTrace, GasChargeEntry{label, amount})
+	}
 	return nil
 }
 
@@ -49,10 +65,18 @@ func (c *Context) Burned() uint64 {
 }
 
 func (c *Context) BurnOut() error {
+	if c.traceGas {
+		c.gasTrace = append(c.gasTrace, GasChargeEntry{"outOfGas", c.gasLeft})
+	}
 	c.gasLeft = 0
 	return vm.ErrOutOfGas
 }
 
+// GasTrace gets the charges made so far, which are only recorded when the call is traced
+func (c *Context) GasTrace() []GasChargeEntry {
+	return c.gasTrace
+}
+
 func (c *Context) GasLeft() *uint64 {
 	return &c.gasLeft
 }
@@ -368,7 +368,7 @@ func MakePrecompile(metadata *bind.MetaData, implementer interface{}) (addr, *Pr
 				// an error occurred during gascost()
 				return []reflect.Value{emitCost[1]}
 			}
-			if err := callerCtx.Burn(cost); err != nil {
+			if err := callerCtx.burnLabeled("event:"+name, cost); err != nil {
 				// the user has run out of gas
 				return []reflect.Value{reflect.ValueOf(vm.ErrOutOfGas)}
 			}
@@ -514,6 +514,10 @@ func MakePrecompile(metadata *bind.MetaData, implementer interface{}) (addr, *Pr
 	}
 }
 
+// dispatchTable maps each precompile's address to its method table for introspection.
+// It's filled the first time Precompiles runs, during init, and only read afterward.
+var dispatchTable map[addr]*Precompile
+
 func Precompiles() map[addr]ArbosPrecompile {
 	contracts := make(map[addr]ArbosPrecompile)
 
@@ -536,8 +540,17 @@ func Precompiles() map[addr]ArbosPrecompile {
 	ArbGasInfo.methodsByName["GetL1PricingFundsDueForRewards"].arbosVersion = 20
 	ArbGasInfo.methodsByName["GetL1PricingUnitsSinceUpdate"].arbosVersion = 20
 	ArbGasInfo.methodsByName["GetLastL1PricingSurplus"].arbosVersion = 20
-	insert(MakePrecompile(pgen.ArbAggregatorMetaData, &ArbAggregator{Address: types.ArbAggregatorAddress}))
-	insert(MakePrecompile(pgen.ArbStatisticsMetaData, &ArbStatistics{Address: types.ArbStatisticsAddress}))
+	ArbGasInfo.methodsByName["GetL1PricingSnapshot"].arbosVersion = 31
+	ArbGasInfo.methodsByName["GetSpeedLimitPerSecond"].arbosVersion = 31
+	ArbGasInfo.methodsByName["GetMinRedeemDonation"].arbosVersion = 31
+	ArbAggregator := insert(MakePrecompile(pgen.ArbAggregatorMetaData, &ArbAggregator{Address: types.ArbAggregatorAddress}))
+	ArbAggregator.methodsByName["IsFeeCollector"].arbosVersion = 31
+	ArbAggregator.methodsByName["GetAggregatorConfig"].arbosVersion = 31
+	ArbAggregator.methodsByName["GetCollectedFees"].arbosVersion = 31
+	ArbAggregator.methodsByName["GetFeeCollectors"].arbosVersion = 31
+	ArbAggregator.methodsByName["GetAggregatorsForCollector"].arbosVersion = 31
+	ArbStatistics := insert(MakePrecompile(pgen.ArbStatisticsMetaData, &ArbStatistics{Address: types.ArbStatisticsAddress}))
+	ArbStatistics.methodsByName["GetRetryableStats"].arbosVersion = 31
 
 	eventCtx := func(gasLimit uint64, err error) *Context {
 		if err != nil {
@@ -578,6 +591,44 @@ func Precompiles() map[addr]ArbosPrecompile {
 
 	ArbRetryableImpl := &ArbRetryableTx{Address: types.ArbRetryableTxAddress}
 	ArbRetryable := insert(MakePrecompile(pgen.ArbRetryableTxMetaData, ArbRetryableImpl))
+	ArbRetryable.methodsByName["GetRentRateWei"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetTotalRentCollected"].arbosVersion = 31
+	ArbRetryable.methodsByName["WasAutoRedeemed"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetRoundingPolicy"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetBeneficiaryHistory"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetRedeemHistory"].arbosVersion = 31
+	ArbRetryable.methodsByName["RedeemAtGasPrice"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetNumTries"].arbosVersion = 31
+	ArbRetryable.methodsByName["RedeemTo"].arbosVersion = 31
+	ArbRetryable.methodsByName["KeepaliveBatch"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetTimeRemaining"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetCurrentTicketId"].arbosVersion = 31
+	ArbRetryable.methodsByName["TransferBeneficiary"].arbosVersion = 31
+	ArbRetryable.methodsByName["RedeemWithGasLimit"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetSubmissionPrice"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetRetryableData"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetTimeouts"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetRetryableSizeBytes"].arbosVersion = 31
+	ArbRetryable.methodsByName["SweepExpired"].arbosVersion = 31
+	ArbRetryable.methodsByName["SimulateRedeem"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetKeepaliveCost"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetEscrowedValue"].arbosVersion = 31
+	ArbRetryable.methodsByName["TryDelete"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetTotalRetryableBytes"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetBeneficiaries"].arbosVersion = 31
+	ArbRetryable.methodsByName["CancelBatch"].arbosVersion = 31
+	ArbRetryable.methodsByName["IsManualRedeemOnly"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetRetryGasPrice"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetInitialTimeout"].arbosVersion = 31
+	ArbRetryable.methodsByName["CancelAndFund"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetMaxRedeemGas"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetKeepaliveCount"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetSubmissionNonce"].arbosVersion = 31
+	ArbRetryable.methodsByName["ProcessTickets"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetCancelGasEstimate"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetMaxTries"].arbosVersion = 31
+	ArbRetryable.methodsByName["GetMaxTriesPolicy"].arbosVersion = 31
+	ArbRetryable.methodsByName["EstimateBatchRedeemOverhead"].arbosVersion = 31
 	arbos.ArbRetryableTxAddress = ArbRetryable.address
 	arbos.RedeemScheduledEventID = ArbRetryable.events["RedeemScheduled"].template.ID
 	arbos.EmitReedeemScheduledEvent = func(
@@ -594,11 +645,17 @@ func Precompiles() map[addr]ArbosPrecompile {
 		context := eventCtx(ArbRetryableImpl.TicketCreatedGasCost(hash{}))
 		return ArbRetryableImpl.TicketCreated(context, evm, ticketId)
 	}
+	arbos.EmitRedeemedEvent = func(evm mech, ticketId bytes32, gasUsed uint64, success bool) error {
+		context := eventCtx(ArbRetryableImpl.RedeemedGasCost(hash{}, 0, false))
+		return ArbRetryableImpl.Redeemed(context, evm, ticketId, gasUsed, success)
+	}
 
 	ArbSys := insert(MakePrecompile(pgen.ArbSysMetaData, &ArbSys{Address: types.ArbSysAddress}))
 	arbos.ArbSysAddress = ArbSys.address
 	arbos.L2ToL1TransactionEventID = ArbSys.events["L2ToL1Transaction"].template.ID
 	arbos.L2ToL1TxEventID = ArbSys.events["L2ToL1Tx"].template.ID
+	ArbSys.methodsByName["GetSupportedSelectors"].arbosVersion = 31
+	ArbSys.methodsByName["GetL2ToL1TxCount"].arbosVersion = 31
 
 	ArbOwnerImpl := &ArbOwner{Address: types.ArbOwnerAddress}
 	emitOwnerActs := func(evm mech, method bytes4, owner addr, data []byte) error {
@@ -611,6 +668,19 @@ func Precompiles() map[addr]ArbosPrecompile {
 	ArbOwner.methodsByName["ReleaseL1PricerSurplusFunds"].arbosVersion = 10
 	ArbOwner.methodsByName["SetChainConfig"].arbosVersion = 11
 	ArbOwner.methodsByName["SetBrotliCompressionLevel"].arbosVersion = 20
+	ArbOwner.methodsByName["SetRetryableRoundingPolicy"].arbosVersion = 31
+	ArbOwner.methodsByName["AddManualRedeemOnlyDestination"].arbosVersion = 31
+	ArbOwner.methodsByName["RemoveManualRedeemOnlyDestination"].arbosVersion = 31
+	ArbOwner.methodsByName["SetMaxRedeemGas"].arbosVersion = 31
+	ArbOwner.methodsByName["ExportRetryable"].arbosVersion = 31
+	ArbOwner.methodsByName["ImportRetryable"].arbosVersion = 31
+	ArbOwner.methodsByName["SetRetryableMaxTries"].arbosVersion = 31
+	ArbOwner.methodsByName["SetRetryableMaxTriesPolicy"].arbosVersion = 31
+	ArbOwner.methodsByName["SetRetryableLifetime"].arbosVersion = 31
+	ArbOwner.methodsByName["SetRetryableMinKeepaliveInterval"].arbosVersion = 31
+	ArbOwner.methodsByName["SetRetryableCancelGracePeriod"].arbosVersion = 31
+	ArbOwner.methodsByName["SetRetryableRedeemChargePerWord"].arbosVersion = 31
+	ArbOwner.methodsByName["SetRetryableMinRedeemDonation"].arbosVersion = 31
 	stylusMethods := []string{
 		"SetInkPrice", "SetWasmMaxStackDepth", "SetWasmFreePages", "SetWasmPageGas",
 		"SetWasmPageLimit", "SetWasmMinInitGas", "SetWasmInitCostScalar",
@@ -624,6 +694,9 @@ func Precompiles() map[addr]ArbosPrecompile {
 	insert(ownerOnly(ArbOwnerImpl.Address, ArbOwner, emitOwnerActs))
 	_, arbDebug := MakePrecompile(pgen.ArbDebugMetaData, &ArbDebug{Address: types.ArbDebugAddress})
 	arbDebug.methodsByName["Panic"].arbosVersion = params.ArbosVersion_Stylus
+	for _, method := range []string{"SetNonce", "SetBalance", "SetCode", "SetStorage"} {
+		arbDebug.methodsByName[method].arbosVersion = 31
+	}
 	insert(debugOnly(arbDebug.address, arbDebug))
 
 	ArbosActs := insert(MakePrecompile(pgen.ArbosActsMetaData, &ArbosActs{Address: types.ArbosAddress}))
@@ -634,6 +707,13 @@ func Precompiles() map[addr]ArbosPrecompile {
 		precompile := contract.Precompile()
 		arbosState.PrecompileMinArbOSVersions[precompile.address] = precompile.arbosVersion
 	}
+	if dispatchTable == nil {
+		table := make(map[addr]*Precompile, len(contracts))
+		for address, contract := range contracts {
+			table[address] = contract.Precompile()
+		}
+		dispatchTable = table
+	}
 
 	return contracts
 }
@@ -706,10 +786,11 @@ func (p *Precompile) Call(
 		gasLeft:     gasSupplied,
 		readOnly:    method.purity <= view,
 		tracingInfo: util.NewTracingInfo(evm, caller, precompileAddress, util.TracingDuringEVM),
+		traceGas:    evm.Config.Tracer != nil,
 	}
 
 	argsCost := params.CopyGas * arbmath.WordsForBytes(uint64(len(input)-4))
-	if err := callerCtx.Burn(argsCost); err != nil {
+	if err := callerCtx.burnLabeled("copy:args", argsCost); err != nil {
 		// user cannot afford the argument data supplied
 		return nil, 0, vm.ErrExecutionReverted
 	}
@@ -775,7 +856,7 @@ func (p *Precompile) Call(
 		isSolErr := errors.As(errRet, &solErr)
 		if isSolErr {
 			resultCost := params.CopyGas * arbmath.WordsForBytes(uint64(len(solErr.data)))
-			if err := callerCtx.Burn(resultCost); err != nil {
+			if err := callerCtx.burnLabeled("copy:revertData", resultCost); err != nil {
 				// user cannot afford the result data returned
 				return nil, 0, vm.ErrExecutionReverted
 			}
@@ -809,7 +890,7 @@ func (p *Precompile) Call(
 	}
 
 	resultCost := params.CopyGas * arbmath.WordsForBytes(uint64(len(encoded)))
-	if err := callerCtx.Burn(resultCost); err != nil {
+	if err := callerCtx.burnLabeled("copy:result", resultCost); err != nil {
 		// user cannot afford the result data returned
 		return nil, 0, vm.ErrExecutionReverted
 	}
@@ -833,6 +914,23 @@ func (p *Precompile) Get4ByteMethodSignatures() [][4]byte {
 	return ret
 }
 
+// SupportedSelectors returns the sorted selectors Call would dispatch at the given ArbOS version
+func (p *Precompile) SupportedSelectors(arbosVersion uint64) [][4]byte {
+	ret := [][4]byte{}
+	if arbosVersion < p.arbosVersion {
+		return ret
+	}
+	for sig, method := range p.methods {
+		if arbosVersion >= method.arbosVersion {
+			ret = append(ret, sig)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return bytes.Compare(ret[i][:], ret[j][:]) < 0
+	})
+	return ret
+}
+
 func (p *Precompile) GetErrorABIs() []abi.Error {
 	ret := make([]abi.Error, 0, len(p.errors))
 	for _, solErr := range p.errors {
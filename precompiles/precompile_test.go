@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/offchainlabs/nitro/arbos/storage"
@@ -180,6 +181,33 @@ func TestEventCosts(t *testing.T) {
 	}
 }
 
+func TestEventsRevertWithSnapshot(t *testing.T) {
+	evm := newMockEVMForTesting()
+	precompileCtx := testContext(common.Address{}, evm)
+	//nolint:errcheck
+	retryableTx := Precompiles()[types.ArbRetryableTxAddress].Precompile().implementer.Interface().(*ArbRetryableTx)
+	//nolint:errcheck
+	statedb := evm.StateDB.(*state.StateDB)
+
+	kept := common.BigToHash(big.NewInt(1))
+	dropped := common.BigToHash(big.NewInt(2))
+	Require(t, retryableTx.Canceled(precompileCtx, evm, kept))
+
+	// events are journaled logs, so reverting the state drops them just as a reorg would
+	snapshot := statedb.Snapshot()
+	Require(t, retryableTx.LifetimeExtended(precompileCtx, evm, dropped, big.NewInt(7)))
+	Require(t, retryableTx.Canceled(precompileCtx, evm, dropped))
+	if len(statedb.Logs()) != 3 {
+		Fail(t, "expected three logs", len(statedb.Logs()))
+	}
+	statedb.RevertToSnapshot(snapshot)
+
+	logs := statedb.Logs()
+	if len(logs) != 1 || logs[0].Topics[1] != kept {
+		Fail(t, "reverting didn't drop the later logs", logs)
+	}
+}
+
 func TestPrecompilesPerArbosVersion(t *testing.T) {
 	// Set up a logger in case log.Crit is called by Precompiles()
 	glogger := log.NewGlogHandler(
@@ -194,6 +222,7 @@ func TestPrecompilesPerArbosVersion(t *testing.T) {
 		11: 4,
 		20: 8,
 		30: 38,
+		31: 66,
 	}
 
 	precompiles := Precompiles()